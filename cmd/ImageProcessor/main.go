@@ -10,10 +10,14 @@ import (
 	"github.com/wb-go/wbf/zlog"
 
 	"ImageProcessor/internal/api"
+	"ImageProcessor/internal/api/auth"
 	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/api/ratelimit"
+	"ImageProcessor/internal/api/resumable"
 	"ImageProcessor/internal/app"
 	"ImageProcessor/internal/config"
 	"ImageProcessor/internal/repository"
+	"ImageProcessor/internal/service"
 )
 
 func main() {
@@ -54,13 +58,66 @@ func main() {
 		cfg.Minio.Password,
 		cfg.Minio.BucketName,
 		cfg.Minio.Sslmode,
+		cfg.Minio.PublicEndpoint,
+		cfg.Minio.PublicRead,
 	)
 	if err != nil {
 		zlog.Logger.Fatal().Msg(err.Error())
 	}
 
-	h := handlers.NewHandler(db, producer, minio)
-	api.SetupRoutes(h, engine)
+	presets, err := service.NewPresets(cfg.Thumbnails.Presets, cfg.Thumbnails.Default)
+	if err != nil {
+		zlog.Logger.Fatal().Msg(err.Error())
+	}
+
+	uploads, err := resumable.NewStore(cfg.Uploads.Dir, cfg.Uploads.MaxSize, cfg.Uploads.SessionTTL)
+	if err != nil {
+		zlog.Logger.Fatal().Msg(err.Error())
+	}
+
+	tenants, err := service.NewTenants(cfg.Tenants, presets)
+	if err != nil {
+		zlog.Logger.Fatal().Msg(err.Error())
+	}
+
+	h := handlers.NewHandler(db, producer, minio, presets, cfg.Server.TransformSecret)
+	h.Tenants = tenants
+	h.Uploads = uploads
+	h.URLExpiry = cfg.Minio.URLExpiry
+	h.AuthRequired = cfg.Auth.Required
+	h.Quota = cfg.Quota
+
+	tokens, err := auth.NewStaticTokens(cfg.Auth.Tokens, cfg.Auth.Admins)
+	if err != nil {
+		zlog.Logger.Fatal().Msg(err.Error())
+	}
+
+	authenticators := []auth.Authenticator{tokens, auth.NewAPIKeys(db)}
+	if cfg.Auth.JWT.Enabled() {
+		jwt, err := auth.NewJWT(cfg.Auth.JWT.JWKSFile, cfg.Auth.JWT.Secret)
+		if err != nil {
+			zlog.Logger.Fatal().Msg(err.Error())
+		}
+		jwt.Issuer = cfg.Auth.JWT.Issuer
+		jwt.Audience = cfg.Auth.JWT.Audience
+		jwt.UserClaim = cfg.Auth.JWT.UserClaim
+		jwt.ScopesClaim = cfg.Auth.JWT.ScopesClaim
+		jwt.TenantClaim = cfg.Auth.JWT.TenantClaim
+		jwt.Admins = cfg.Auth.Admins
+		jwt.Leeway = cfg.Auth.JWT.Leeway
+		jwt.AllowNoExpiry = cfg.Auth.JWT.AllowNoExpiry
+		authenticators = append(authenticators, jwt)
+	}
+
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.RPS > 0 {
+		limiter = ratelimit.New(cfg.RateLimit.RPS, cfg.RateLimit.Burst)
+	}
+
+	api.SetupRoutes(h, engine, auth.Middleware(cfg.Auth.Required, authenticators...), limiter.Middleware())
+
+	fetcher := service.NewFetcher(cfg.Uploads.FetchMaxSize, cfg.Uploads.FetchTimeout)
+	fetcher.Tenants = tenants
 
 	a := app.App{
 		DB:           db,
@@ -69,6 +126,7 @@ func main() {
 		Handler:      engine,
 		Host:         ":" + cfg.Server.Port,
 		ImageStorage: minio,
+		Fetcher:      fetcher,
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
@@ -3,16 +3,67 @@ package api
 import (
 	"github.com/wb-go/wbf/ginext"
 
+	"ImageProcessor/internal/api/auth"
 	"ImageProcessor/internal/api/handlers"
 )
 
-func SetupRoutes(h *handlers.Handler, g *ginext.Engine) {
+// SetupRoutes registers the routes. The page, the presets and the signed
+// transformation URLs are public, the other routes go through authenticate
+// and need the scope of their group, managing API keys needs an
+// authenticated client. limit runs after authentication on every route
+// except the page and the presets.
+func SetupRoutes(h *handlers.Handler, g *ginext.Engine, authenticate, limit ginext.HandlerFunc) {
 	g.Use(ginext.Logger(), ginext.Recovery())
 	g.LoadHTMLGlob("web/*.html")
 
-	g.POST("/upload", h.UploadImage)
-	g.GET("/image/:id", h.GetImage)
-	g.GET("/images", h.GetImages)
-	g.DELETE("/image/:id", h.DeleteImage)
 	g.GET("/", h.Home)
+	g.GET("/presets", h.GetPresets)
+	g.GET("/t/:signature/:options/:id", limit, h.GetTransformedImage)
+
+	r := g.Group("/", authenticate, limit)
+
+	upload := r.Group("/", auth.RequireScope(auth.ScopeUpload))
+	upload.POST("/upload", h.UploadImage)
+	upload.POST("/upload/batch", h.UploadBatch)
+	upload.POST("/upload/url", h.UploadFromURL)
+	upload.POST("/upload/resumable", h.CreateUpload)
+	upload.HEAD("/upload/resumable/:id", h.GetUploadOffset)
+	upload.GET("/upload/resumable/:id", h.GetUploadOffset)
+	upload.PATCH("/upload/resumable/:id", h.PatchUpload)
+	upload.POST("/upload/resumable/:id/complete", h.CompleteUpload)
+	upload.DELETE("/upload/resumable/:id", h.DeleteUpload)
+	upload.PATCH("/image/:id", h.UpdateVisibility)
+	upload.POST("/image/:id/process", h.ReprocessImage)
+	upload.PUT("/image/:id/tags/:tag", h.TagImage)
+	upload.DELETE("/image/:id/tags/:tag", h.UntagImage)
+	upload.POST("/albums", h.CreateAlbum)
+	upload.PUT("/albums/:id/images/:image_id", h.AddAlbumImage)
+	upload.DELETE("/albums/:id/images/:image_id", h.RemoveAlbumImage)
+
+	read := r.Group("/", auth.RequireScope(auth.ScopeRead))
+	read.GET("/image/:id", h.GetImage)
+	read.GET("/image/:id/content", h.GetImageContent)
+	read.HEAD("/image/:id/content", h.GetImageContent)
+	read.GET("/image/:id/original", h.GetImageOriginal)
+	read.HEAD("/image/:id/original", h.GetImageOriginal)
+	read.GET("/images", h.GetImages)
+	read.GET("/image/:id/derivatives", h.GetDerivatives)
+	read.GET("/transformations", h.GetTransformations)
+	read.GET("/transformations/:name", h.GetTransformation)
+	read.GET("/albums", h.GetAlbums)
+	read.GET("/albums/:id", h.GetAlbum)
+
+	r.DELETE("/image/:id", auth.RequireScope(auth.ScopeDelete), h.DeleteImage)
+	r.DELETE("/albums/:id", auth.RequireScope(auth.ScopeDelete), h.DeleteAlbum)
+
+	admin := r.Group("/", auth.RequireScope(auth.ScopeAdmin))
+	admin.POST("/transformations", h.CreateTransformation)
+	admin.PUT("/transformations/:name", h.UpdateTransformation)
+	admin.DELETE("/transformations/:name", h.DeleteTransformation)
+
+	keys := r.Group("/keys", auth.RequireIdentity())
+	keys.POST("", h.CreateAPIKey)
+	keys.GET("", h.GetAPIKeys)
+	keys.POST("/:id/rotate", h.RotateAPIKey)
+	keys.DELETE("/:id", h.RevokeAPIKey)
 }
@@ -0,0 +1,74 @@
+package apitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api"
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func TestTransformationRoutesRequireAdmin(t *testing.T) {
+	t.Chdir("../../..")
+
+	tokens, err := auth.NewStaticTokens("s3cret=alice", nil)
+	require.NoError(t, err)
+
+	h := handlers.NewHandler(mocks.NewMockStorager(t), nil, mocks.NewMockImageStore(t), nil, "")
+	engine := ginext.New("test")
+	api.SetupRoutes(h, engine, auth.Middleware(false, tokens), func(c *gin.Context) { c.Next() })
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		header         string
+		expectedStatus int
+	}{
+		{name: "anonymous create", method: "POST", target: "/transformations", expectedStatus: http.StatusUnauthorized},
+		{name: "anonymous update", method: "PUT", target: "/transformations/card", expectedStatus: http.StatusUnauthorized},
+		{name: "anonymous delete", method: "DELETE", target: "/transformations/card", expectedStatus: http.StatusUnauthorized},
+		{name: "user create", method: "POST", target: "/transformations", header: "Bearer s3cret", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader("name=card&steps=[]"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			engine.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestKeyRoutesRequireIdentity(t *testing.T) {
+	t.Chdir("../../..")
+
+	h := handlers.NewHandler(mocks.NewMockStorager(t), nil, mocks.NewMockImageStore(t), nil, "")
+	engine := ginext.New("test")
+	api.SetupRoutes(h, engine, auth.Middleware(false), func(c *gin.Context) { c.Next() })
+
+	for _, route := range [][2]string{
+		{"POST", "/keys"},
+		{"GET", "/keys"},
+		{"POST", "/keys/1/rotate"},
+		{"DELETE", "/keys/1"},
+	} {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(route[0], route[1], nil))
+		require.Equal(t, http.StatusUnauthorized, rr.Code, route[1])
+	}
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+)
+
+const (
+	ScopeUpload = "upload"
+	ScopeRead   = "read"
+	ScopeDelete = "delete"
+)
+
+// Scopes lists every scope an API key may have.
+var Scopes = []string{ScopeUpload, ScopeRead, ScopeDelete, ScopeAdmin}
+
+const (
+	apiKeyTag         = "ipk_"
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+	// touchInterval limits how often the last used time of a key is written.
+	touchInterval = time.Minute
+)
+
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+type KeyStore interface {
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error)
+	TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error
+}
+
+// APIKeys authenticates "Authorization: Bearer ipk_<prefix>_<secret>" keys
+// stored in a KeyStore.
+type APIKeys struct {
+	store KeyStore
+}
+
+func NewAPIKeys(store KeyStore) *APIKeys {
+	return &APIKeys{store: store}
+}
+
+// GenerateAPIKey returns a new key together with its prefix and hash, the
+// key itself is shown to the client once and never stored.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	key = apiKeyTag + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return key, prefix, HashAPIKey(key), nil
+}
+
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *APIKeys) Authenticate(r *http.Request) (Identity, error) {
+	token, ok := BearerToken(r)
+	if !ok || !strings.HasPrefix(token, apiKeyTag) {
+		return Identity{}, ErrNoCredentials
+	}
+
+	prefix, _, ok := strings.Cut(strings.TrimPrefix(token, apiKeyTag), "_")
+	if !ok || len(prefix) != 2*apiKeyPrefixBytes {
+		return Identity{}, ErrBadCredentials
+	}
+
+	ctx := r.Context()
+	key, err := a.store.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Identity{}, ErrBadCredentials
+		}
+		return Identity{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(HashAPIKey(token)), []byte(key.KeyHash)) != 1 {
+		return Identity{}, ErrBadCredentials
+	}
+	if key.RevokedAt != nil {
+		return Identity{}, fmt.Errorf("api key revoked: %w", ErrBadCredentials)
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		if err := a.store.TouchAPIKey(ctx, key.ID, now); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}
+
+	return Identity{UserID: key.UserID, Tenant: key.Tenant, Scopes: key.Scopes}, nil
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+)
+
+const ScopeAdmin = "admin"
+
+const (
+	identityKey  = "identity"
+	anonymousKey = "anonymous"
+)
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request has
+	// no credentials it understands, so the next one is tried.
+	ErrNoCredentials  = fmt.Errorf("no credentials")
+	ErrBadCredentials = fmt.Errorf("invalid credentials")
+)
+
+// Identity is the authenticated client of a request. Users are only unique
+// within their tenant.
+type Identity struct {
+	UserID string   `json:"user_id"`
+	Tenant string   `json:"tenant"`
+	Scopes []string `json:"scopes"`
+}
+
+func (i Identity) HasScope(scope string) bool {
+	return slices.Contains(i.Scopes, scope) || slices.Contains(i.Scopes, ScopeAdmin)
+}
+
+func (i Identity) IsAdmin() bool {
+	return slices.Contains(i.Scopes, ScopeAdmin)
+}
+
+type Authenticator interface {
+	Authenticate(r *http.Request) (Identity, error)
+}
+
+// Middleware stores the identity given by the first authenticator that
+// recognizes the credentials of the request. Requests with credentials no
+// authenticator accepts get 401, requests without credentials get 401 only
+// when required is set and continue anonymously otherwise. Other errors of
+// an authenticator give 500.
+func Middleware(required bool, authenticators ...Authenticator) ginext.HandlerFunc {
+	return func(c *ginext.Context) {
+		for _, a := range authenticators {
+			identity, err := a.Authenticate(c.Request)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			if errors.Is(err, ErrBadCredentials) {
+				unauthorized(c, err)
+				return
+			}
+			if err != nil {
+				zlog.Logger.Error().Msg(err.Error())
+				c.AbortWithStatusJSON(http.StatusInternalServerError, ginext.H{
+					"error": "authentication failed",
+				})
+				return
+			}
+			c.Set(identityKey, identity)
+			c.Next()
+			return
+		}
+
+		if c.GetHeader("Authorization") != "" {
+			unauthorized(c, ErrBadCredentials)
+			return
+		}
+		if required {
+			unauthorized(c, fmt.Errorf("authentication required"))
+			return
+		}
+		c.Set(anonymousKey, true)
+		c.Next()
+	}
+}
+
+// RequireScope rejects authenticated clients without the scope with 403.
+// Anonymous requests pass for ScopeRead. For ScopeUpload and ScopeDelete they
+// pass only when the Middleware let them continue anonymously, so routes
+// without the Middleware in front fail closed. Other scopes, ScopeAdmin
+// among them, always give anonymous requests 401.
+func RequireScope(scope string) ginext.HandlerFunc {
+	return func(c *ginext.Context) {
+		identity, ok := FromContext(c)
+		if !ok {
+			if !anonymousAllowed(c, scope) {
+				unauthorized(c, fmt.Errorf("authentication required"))
+				return
+			}
+			c.Next()
+			return
+		}
+		if !identity.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ginext.H{
+				"error": fmt.Sprintf("missing scope %s", scope),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+func anonymousAllowed(c *ginext.Context, scope string) bool {
+	switch scope {
+	case ScopeRead:
+		return true
+	case ScopeUpload, ScopeDelete:
+		return c.GetBool(anonymousKey)
+	}
+	return false
+}
+
+// RequireIdentity rejects anonymous requests with 401 whatever the Middleware
+// allows, the scopes of the client are left to the handler.
+func RequireIdentity() ginext.HandlerFunc {
+	return func(c *ginext.Context) {
+		if _, ok := FromContext(c); !ok {
+			unauthorized(c, fmt.Errorf("authentication required"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// FromContext returns the identity of the request, ok is false for
+// anonymous requests.
+func FromContext(c *ginext.Context) (Identity, bool) {
+	v, ok := c.Get(identityKey)
+	if !ok {
+		return Identity{}, false
+	}
+	identity, ok := v.(Identity)
+	return identity, ok
+}
+
+// Tenant returns the tenant of the request, anonymous requests belong to the
+// default tenant.
+func Tenant(c *ginext.Context) string {
+	identity, ok := FromContext(c)
+	if !ok || identity.Tenant == "" {
+		return model.DefaultTenant
+	}
+	return identity.Tenant
+}
+
+// SetIdentity attaches an identity to the request, handler tests use it in
+// place of the middleware.
+func SetIdentity(c *ginext.Context, identity Identity) {
+	c.Set(identityKey, identity)
+}
+
+// BearerToken returns the token of an "Authorization: Bearer" header.
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(c *ginext.Context, err error) {
+	c.Header("WWW-Authenticate", `Bearer realm="ImageProcessor"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, ginext.H{
+		"error": err.Error(),
+	})
+}
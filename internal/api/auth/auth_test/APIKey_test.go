@@ -0,0 +1,174 @@
+package authtest
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key, "ipk_"+prefix+"_"))
+	require.Equal(t, auth.HashAPIKey(key), hash)
+	require.NotContains(t, hash, key)
+
+	other, _, _, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, key, other)
+}
+
+func TestAPIKeys(t *testing.T) {
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+
+	recent := time.Now().Add(-time.Second)
+	revoked := time.Now().Add(-time.Hour)
+	stored := model.APIKey{ID: 3, UserID: "alice", Prefix: prefix, KeyHash: hash, Scopes: []string{auth.ScopeRead}}
+
+	tests := []struct {
+		name           string
+		header         string
+		setupMock      func(*mocks.MockStorager)
+		expectedStatus int
+		expectedUser   string
+	}{
+		{
+			name:   "valid key",
+			header: "Bearer " + key,
+			setupMock: func(m *mocks.MockStorager) {
+				m.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(stored, nil).Once()
+				m.On("TouchAPIKey", mock.Anything, 3, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedUser:   "alice",
+		},
+		{
+			name:   "recently used key is not touched",
+			header: "Bearer " + key,
+			setupMock: func(m *mocks.MockStorager) {
+				k := stored
+				k.LastUsedAt = &recent
+				m.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(k, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedUser:   "alice",
+		},
+		{
+			name:   "wrong secret",
+			header: "Bearer " + key[:len(key)-2] + "xx",
+			setupMock: func(m *mocks.MockStorager) {
+				m.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(stored, nil).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "revoked key",
+			header: "Bearer " + key,
+			setupMock: func(m *mocks.MockStorager) {
+				k := stored
+				k.RevokedAt = &revoked
+				m.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(k, nil).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "unknown prefix",
+			header: "Bearer " + key,
+			setupMock: func(m *mocks.MockStorager) {
+				m.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(model.APIKey{}, sql.ErrNoRows).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "malformed key",
+			header:         "Bearer ipk_short",
+			setupMock:      func(m *mocks.MockStorager) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "storage error",
+			header: "Bearer " + key,
+			setupMock: func(m *mocks.MockStorager) {
+				m.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(model.APIKey{}, fmt.Errorf("connection refused")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			tt.setupMock(mockDB)
+
+			var identity auth.Identity
+			r := gin.New()
+			r.GET("/", auth.Middleware(false, auth.NewAPIKeys(mockDB)), func(c *gin.Context) {
+				identity, _ = auth.FromContext(c)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Authorization", tt.header)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			require.Equal(t, tt.expectedUser, identity.UserID)
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		scope          string
+		identity       *auth.Identity
+		anonymous      bool
+		expectedStatus int
+	}{
+		{name: "has scope", scope: auth.ScopeRead, identity: &auth.Identity{UserID: "alice", Scopes: []string{auth.ScopeRead}}, expectedStatus: http.StatusOK},
+		{name: "admin", scope: auth.ScopeRead, identity: &auth.Identity{UserID: "root", Scopes: []string{auth.ScopeAdmin}}, expectedStatus: http.StatusOK},
+		{name: "missing scope", scope: auth.ScopeRead, identity: &auth.Identity{UserID: "alice", Scopes: []string{auth.ScopeUpload}}, expectedStatus: http.StatusForbidden},
+		{name: "anonymous", scope: auth.ScopeRead, expectedStatus: http.StatusOK},
+		{name: "anonymous upload", scope: auth.ScopeUpload, expectedStatus: http.StatusUnauthorized},
+		{name: "anonymous delete", scope: auth.ScopeDelete, expectedStatus: http.StatusUnauthorized},
+		{name: "anonymous upload allowed", scope: auth.ScopeUpload, anonymous: true, expectedStatus: http.StatusOK},
+		{name: "anonymous admin", scope: auth.ScopeAdmin, expectedStatus: http.StatusUnauthorized},
+		{name: "anonymous admin allowed", scope: auth.ScopeAdmin, anonymous: true, expectedStatus: http.StatusUnauthorized},
+		{name: "not an admin", scope: auth.ScopeAdmin, identity: &auth.Identity{UserID: "alice", Scopes: []string{auth.ScopeUpload}}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			if tt.anonymous {
+				r.Use(auth.Middleware(false))
+			}
+			r.GET("/", func(c *gin.Context) {
+				if tt.identity != nil {
+					auth.SetIdentity(c, *tt.identity)
+				}
+			}, auth.RequireScope(tt.scope), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
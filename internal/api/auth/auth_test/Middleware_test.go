@@ -0,0 +1,83 @@
+package authtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+func TestMiddleware(t *testing.T) {
+	tokens, err := auth.NewStaticTokens("s3cret=alice, t0ken=root", []string{"root"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		required       bool
+		header         string
+		expectedStatus int
+		expectedUser   string
+		expectedAdmin  bool
+	}{
+		{name: "valid token", header: "Bearer s3cret", expectedStatus: http.StatusOK, expectedUser: "alice"},
+		{name: "admin token", header: "bearer t0ken", expectedStatus: http.StatusOK, expectedUser: "root", expectedAdmin: true},
+		{name: "unknown token", header: "Bearer nope", expectedStatus: http.StatusUnauthorized},
+		{name: "other scheme", header: "Basic YWxpY2U6cGFzcw==", expectedStatus: http.StatusUnauthorized},
+		{name: "anonymous", expectedStatus: http.StatusOK},
+		{name: "anonymous when required", required: true, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var identity auth.Identity
+			var authenticated bool
+
+			r := gin.New()
+			r.GET("/", auth.Middleware(tt.required, tokens), func(c *gin.Context) {
+				identity, authenticated = auth.FromContext(c)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				require.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+				return
+			}
+			require.Equal(t, tt.expectedUser != "", authenticated)
+			require.Equal(t, tt.expectedUser, identity.UserID)
+			require.Equal(t, tt.expectedAdmin, identity.IsAdmin())
+		})
+	}
+}
+
+func TestNewStaticTokensInvalid(t *testing.T) {
+	_, err := auth.NewStaticTokens("just-a-token", nil)
+	require.Error(t, err)
+
+	_, err = auth.NewStaticTokens("s3cret=alice:Bad/Tenant", nil)
+	require.Error(t, err)
+}
+
+func TestStaticTokensTenant(t *testing.T) {
+	tokens, err := auth.NewStaticTokens("a=alice, b=bob:brand", nil)
+	require.NoError(t, err)
+
+	for token, expected := range map[string]string{"a": "default", "b": "brand"} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		identity, err := tokens.Authenticate(req)
+		require.NoError(t, err)
+		require.Equal(t, expected, identity.Tenant)
+	}
+}
@@ -0,0 +1,294 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+)
+
+// jwtKey is a verification key of a JWKS file or the static secret.
+type jwtKey struct {
+	id     string
+	alg    string
+	rsa    *rsa.PublicKey
+	ec     *ecdsa.PublicKey
+	secret []byte
+}
+
+// JWT authenticates bearer JSON Web Tokens signed with RS256, ES256 or
+// HS256. The user id is read from UserClaim, the scopes from ScopesClaim,
+// which may be a space separated string or an array, and the tenant from
+// TenantClaim, tokens without it belong to the default tenant. Issuer and
+// Audience are checked when set. Tokens without exp are rejected unless
+// AllowNoExpiry is set.
+type JWT struct {
+	UserClaim   string
+	ScopesClaim string
+	TenantClaim string
+	Issuer      string
+	Audience    string
+	Admins      []string
+	Leeway      time.Duration
+	// AllowNoExpiry accepts tokens without the exp claim.
+	AllowNoExpiry bool
+
+	keys []jwtKey
+}
+
+// NewJWT loads the keys of a JWKS file and adds secret as an HS256 key,
+// either of them may be empty.
+func NewJWT(jwksFile, secret string) (*JWT, error) {
+	j := &JWT{UserClaim: "sub", ScopesClaim: "scope", TenantClaim: "tenant"}
+
+	if jwksFile != "" {
+		data, err := os.ReadFile(jwksFile)
+		if err != nil {
+			return nil, err
+		}
+		j.keys, err = parseJWKS(data)
+		if err != nil {
+			return nil, fmt.Errorf("jwks %s: %w", jwksFile, err)
+		}
+	}
+	if secret != "" {
+		j.keys = append(j.keys, jwtKey{alg: "HS256", secret: []byte(secret)})
+	}
+	if len(j.keys) == 0 {
+		return nil, fmt.Errorf("no jwt keys configured")
+	}
+	return j, nil
+}
+
+func (j *JWT) Authenticate(r *http.Request) (Identity, error) {
+	token, ok := BearerToken(r)
+	if !ok || strings.Count(token, ".") != 2 {
+		return Identity{}, ErrNoCredentials
+	}
+
+	claims, err := j.verify(token)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrBadCredentials, err)
+	}
+
+	userID, _ := claims[j.UserClaim].(string)
+	if userID == "" {
+		return Identity{}, fmt.Errorf("%w: token has no %s claim", ErrBadCredentials, j.UserClaim)
+	}
+
+	tenant, _ := claims[j.TenantClaim].(string)
+	if tenant == "" {
+		tenant = model.DefaultTenant
+	}
+	if !repository.ValidTenant(tenant) {
+		return Identity{}, fmt.Errorf("%w: invalid tenant", ErrBadCredentials)
+	}
+
+	identity := Identity{UserID: userID, Tenant: tenant, Scopes: claimScopes(claims[j.ScopesClaim])}
+	if slices.Contains(j.Admins, userID) && !identity.IsAdmin() {
+		identity.Scopes = append(identity.Scopes, ScopeAdmin)
+	}
+	return identity, nil
+}
+
+func (j *JWT) verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid header")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature")
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range j.keys {
+		if header.Kid != "" && key.id != "" && key.id != header.Kid {
+			continue
+		}
+		ok, err := key.verify(header.Alg, signed, signature)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("signature verification failed")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid claims")
+	}
+	return claims, j.validate(claims)
+}
+
+func (j *JWT) validate(claims map[string]interface{}) error {
+	now := time.Now()
+
+	exp, ok := claims["exp"].(float64)
+	switch {
+	case !ok && !j.AllowNoExpiry:
+		return fmt.Errorf("token has no expiry")
+	case ok && now.After(time.Unix(int64(exp), 0).Add(j.Leeway)):
+		return fmt.Errorf("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok {
+		if now.Add(j.Leeway).Before(time.Unix(int64(nbf), 0)) {
+			return fmt.Errorf("token not valid yet")
+		}
+	}
+	if j.Issuer != "" && claims["iss"] != j.Issuer {
+		return fmt.Errorf("unexpected issuer")
+	}
+	if j.Audience != "" && !slices.Contains(claimScopes(claims["aud"]), j.Audience) {
+		return fmt.Errorf("unexpected audience")
+	}
+	return nil
+}
+
+// verify reports whether signature is valid for alg. Keys of another type
+// than alg are skipped, so a public key can never be used as an HMAC secret.
+func (k jwtKey) verify(alg string, signed, signature []byte) (bool, error) {
+	if k.alg != "" && k.alg != alg {
+		return false, nil
+	}
+	digest := sha256.Sum256(signed)
+
+	switch alg {
+	case "HS256":
+		if k.secret == nil {
+			return false, nil
+		}
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(signed)
+		return hmac.Equal(signature, mac.Sum(nil)), nil
+	case "RS256":
+		if k.rsa == nil {
+			return false, nil
+		}
+		return rsa.VerifyPKCS1v15(k.rsa, crypto.SHA256, digest[:], signature) == nil, nil
+	case "ES256":
+		if k.ec == nil || k.ec.Curve != elliptic.P256() {
+			return false, nil
+		}
+		if len(signature) != 64 {
+			return false, nil
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(k.ec, digest[:], r, s), nil
+	default:
+		return false, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+}
+
+func parseJWKS(data []byte) ([]jwtKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make([]jwtKey, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key := jwtKey{id: jwk.Kid, alg: jwk.Alg}
+
+		switch jwk.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: invalid n", jwk.Kid)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+			if err != nil || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("key %q: invalid e", jwk.Kid)
+			}
+			key.rsa = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			if jwk.Crv != "P-256" {
+				return nil, fmt.Errorf("key %q: unsupported curve %q", jwk.Kid, jwk.Crv)
+			}
+			x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+			y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+			if errX != nil || errY != nil {
+				return nil, fmt.Errorf("key %q: invalid coordinates", jwk.Kid)
+			}
+			key.ec = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		case "oct":
+			k, err := base64.RawURLEncoding.DecodeString(jwk.K)
+			if err != nil || len(k) == 0 {
+				return nil, fmt.Errorf("key %q: invalid k", jwk.Kid)
+			}
+			key.secret = k
+		default:
+			return nil, fmt.Errorf("key %q: unsupported key type %q", jwk.Kid, jwk.Kty)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// claimScopes accepts "a b c" as well as ["a", "b", "c"].
+func claimScopes(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		scopes := make([]string, 0, len(v))
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+		return scopes
+	}
+	return nil
+}
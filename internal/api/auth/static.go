@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+)
+
+// StaticTokens authenticates bearer tokens listed in the configuration.
+type StaticTokens struct {
+	tokens map[[sha256.Size]byte]Identity
+}
+
+// NewStaticTokens parses comma separated "token=user_id" pairs, the user may
+// be followed by ":tenant" and belongs to the default tenant otherwise. Tokens
+// have the upload, read and delete scopes, users listed in admins also get
+// the admin scope.
+func NewStaticTokens(tokens string, admins []string) (*StaticTokens, error) {
+	s := &StaticTokens{tokens: make(map[[sha256.Size]byte]Identity)}
+	for _, pair := range strings.Split(tokens, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		token, userID, ok := strings.Cut(pair, "=")
+		if !ok || token == "" || userID == "" {
+			return nil, fmt.Errorf("invalid auth token entry, want token=user_id")
+		}
+		userID, tenant, ok := strings.Cut(userID, ":")
+		if !ok {
+			tenant = model.DefaultTenant
+		}
+		if !repository.ValidTenant(tenant) {
+			return nil, fmt.Errorf("invalid tenant %q of user %s", tenant, userID)
+		}
+
+		identity := Identity{UserID: userID, Tenant: tenant, Scopes: []string{ScopeUpload, ScopeRead, ScopeDelete}}
+		if slices.Contains(admins, userID) {
+			identity.Scopes = append(identity.Scopes, ScopeAdmin)
+		}
+		s.tokens[sha256.Sum256([]byte(token))] = identity
+	}
+	return s, nil
+}
+
+func (s *StaticTokens) Authenticate(r *http.Request) (Identity, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		return Identity{}, ErrNoCredentials
+	}
+
+	// Comparing hashes keeps the lookup independent of the token bytes.
+	sum := sha256.Sum256([]byte(token))
+	for key, identity := range s.tokens {
+		if subtle.ConstantTimeCompare(key[:], sum[:]) == 1 {
+			return identity, nil
+		}
+	}
+	return Identity{}, ErrNoCredentials
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+// AddAlbumImage appends an image the client may read to the end of the
+// album, adding an image twice keeps its position.
+func (h *Handler) AddAlbumImage(c *ginext.Context) {
+	albumID, imageID, ok := albumImageParams(c)
+	if !ok {
+		return
+	}
+
+	_, status, err := h.authorizedAlbum(c, albumID, true)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+	_, status, err = h.authorizedImage(c, imageID, false)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	err = h.DB.AddAlbumImage(c.Request.Context(), auth.Tenant(c), albumID, imageID)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"album_id": albumID,
+		"image_id": imageID,
+	})
+}
+
+// RemoveAlbumImage takes an image out of the album, the image is kept.
+func (h *Handler) RemoveAlbumImage(c *ginext.Context) {
+	albumID, imageID, ok := albumImageParams(c)
+	if !ok {
+		return
+	}
+
+	_, status, err := h.authorizedAlbum(c, albumID, true)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	err = h.DB.RemoveAlbumImage(c.Request.Context(), auth.Tenant(c), albumID, imageID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("image is not in the album"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"result": "image removed from album",
+	})
+}
+
+func albumImageParams(c *ginext.Context) (int, int, bool) {
+	albumID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	imageID, err := strconv.Atoi(c.Param("image_id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return albumID, imageID, true
+}
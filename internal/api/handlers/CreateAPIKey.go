@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+)
+
+// CreateAPIKey issues a key with the comma separated scopes of the form.
+// Clients cannot grant scopes they do not have, admins may create keys of
+// other users with user_id. The key is only returned here.
+func (h *Handler) CreateAPIKey(c *ginext.Context) {
+	identity, ok := auth.FromContext(c)
+	if !ok {
+		WriteJSONError(c, fmt.Errorf("authentication required"), http.StatusUnauthorized)
+		return
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		WriteJSONError(c, fmt.Errorf("name is required"), http.StatusBadRequest)
+		return
+	}
+
+	userID := identity.UserID
+	if other := c.PostForm("user_id"); other != "" && other != userID {
+		if !identity.IsAdmin() {
+			WriteJSONError(c, errForbidden, http.StatusForbidden)
+			return
+		}
+		userID = other
+	}
+
+	scopes, err := parseScopes(c.PostForm("scopes"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+	for _, scope := range scopes {
+		if !identity.HasScope(scope) {
+			WriteJSONError(c, fmt.Errorf("cannot grant scope %s", scope), http.StatusForbidden)
+			return
+		}
+	}
+
+	secret, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	key, err := h.DB.CreateAPIKey(c.Request.Context(), model.APIKey{
+		Tenant:  auth.Tenant(c),
+		UserID:  userID,
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: hash,
+		Scopes:  scopes,
+	})
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusCreated, ginext.H{
+		"key":     secret,
+		"api_key": key,
+	})
+}
+
+func parseScopes(s string) ([]string, error) {
+	scopes := make([]string, 0)
+	for _, scope := range strings.Split(s, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if !auth.ValidScope(scope) {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("scopes are required")
+	}
+	return scopes, nil
+}
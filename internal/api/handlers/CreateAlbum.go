@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+)
+
+const maxAlbumName = 100
+
+// CreateAlbum creates an empty album owned by the client.
+func (h *Handler) CreateAlbum(c *ginext.Context) {
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" || len(name) > maxAlbumName {
+		WriteJSONError(c, fmt.Errorf("name must have 1 to %d characters", maxAlbumName), http.StatusBadRequest)
+		return
+	}
+
+	album := model.Album{Tenant: auth.Tenant(c), Owner: clientUserID(c), Name: name}
+	album, err := h.DB.CreateAlbum(c.Request.Context(), album)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusCreated, album)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+	"ImageProcessor/internal/service"
+)
+
+func (h *Handler) CreateTransformation(c *ginext.Context) {
+	t := model.Transformation{Tenant: auth.Tenant(c), Name: c.PostForm("name")}
+
+	watermark, err := getTransformationFields(c, &t)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	if watermark != nil {
+		err = h.uploadFormFile(watermark, t.WatermarkPath)
+		if err != nil {
+			WriteJSONError(c, err, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	created, err := h.DB.CreateTransformation(c.Request.Context(), t)
+	if err != nil {
+		if watermark != nil {
+			h.deleteObject(c, t.WatermarkPath)
+		}
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			WriteJSONError(c, fmt.Errorf("transformation %s already exists", t.Name), http.StatusConflict)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// getTransformationFields reads steps, format, quality and the optional
+// watermark file into t and validates the result. A new watermark gets its
+// object name in t.WatermarkPath and is returned for uploading.
+func getTransformationFields(c *ginext.Context, t *model.Transformation) (*multipart.FileHeader, error) {
+	err := json.Unmarshal([]byte(c.PostForm("steps")), &t.Steps)
+	if err != nil {
+		return nil, fmt.Errorf("invalid steps: %w", err)
+	}
+
+	t.Format = c.PostForm("format")
+
+	t.Quality = 0
+	qualityStr := c.PostForm("quality")
+	if qualityStr != "" {
+		t.Quality, err = strconv.Atoi(qualityStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	watermark, err := c.FormFile("watermark")
+	switch {
+	case errors.Is(err, http.ErrMissingFile):
+		watermark = nil
+	case err != nil:
+		return nil, err
+	default:
+		ext := filepath.Ext(watermark.Filename)
+		if !slices.Contains(imageExtensions, ext) {
+			return nil, errWatermarkFormat
+		}
+		t.WatermarkPath = objectNameFor(watermark, t.Tenant, "transformations")
+	}
+
+	if err := service.ValidateTransformation(*t); err != nil {
+		return nil, err
+	}
+	return watermark, nil
+}
+
+func (h *Handler) deleteObject(c *ginext.Context, objectName string) {
+	if err := h.ImageStorage.Delete(c.Request.Context(), objectName); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+// DeleteAlbum deletes the album, its images are kept.
+func (h *Handler) DeleteAlbum(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	_, status, err := h.authorizedAlbum(c, id, true)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	err = h.DB.DeleteAlbum(c.Request.Context(), auth.Tenant(c), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"result": "album deleted",
+	})
+}
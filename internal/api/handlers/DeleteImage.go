@@ -8,8 +8,16 @@ import (
 	"strconv"
 
 	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/repository"
 )
 
+// DeleteImage removes the image row and then its original, the processed
+// object, the derivatives and the variants cached under processed/t/<id>/.
+// The derivative rows go with the image, so their objects are looked up
+// first.
 func (h *Handler) DeleteImage(c *ginext.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -18,7 +26,19 @@ func (h *Handler) DeleteImage(c *ginext.Context) {
 		return
 	}
 
-	err = h.DB.DeleteImage(c.Request.Context(), id)
+	img, status, err := h.authorizedImage(c, id, true)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	derivatives, err := h.DB.GetDerivatives(c.Request.Context(), auth.Tenant(c), id)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = h.DB.DeleteImage(c.Request.Context(), auth.Tenant(c), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
@@ -27,6 +47,21 @@ func (h *Handler) DeleteImage(c *ginext.Context) {
 		WriteJSONError(c, err, http.StatusInternalServerError)
 		return
 	}
+
+	objectNames := []string{img.UploadsPath, img.ProcessedPath}
+	for _, d := range derivatives {
+		objectNames = append(objectNames, d.ProcessedPath)
+	}
+	for _, objectName := range objectNames {
+		if objectName != "" {
+			h.deleteObject(c, objectName)
+		}
+	}
+	variants := repository.TenantKey(auth.Tenant(c), fmt.Sprintf("processed/t/%d/", id))
+	if err := h.ImageStorage.DeletePrefix(c.Request.Context(), variants); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+	}
+
 	c.JSON(http.StatusOK, ginext.H{
 		"result": "image delete",
 	})
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+// DeleteTransformation removes the stored transformation. Its watermark
+// object is kept, uploads already queued with the transformation still read
+// it.
+func (h *Handler) DeleteTransformation(c *ginext.Context) {
+	t, err := h.DB.GetTransformation(c.Request.Context(), auth.Tenant(c), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = h.DB.DeleteTransformation(c.Request.Context(), auth.Tenant(c), t.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"result": "transformation delete",
+	})
+}
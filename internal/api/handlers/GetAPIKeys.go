@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+// GetAPIKeys lists the keys of the client, admins may list the keys of
+// another user with user_id.
+func (h *Handler) GetAPIKeys(c *ginext.Context) {
+	identity, ok := auth.FromContext(c)
+	if !ok {
+		WriteJSONError(c, fmt.Errorf("authentication required"), http.StatusUnauthorized)
+		return
+	}
+
+	userID := identity.UserID
+	if other := c.Query("user_id"); other != "" && other != userID {
+		if !identity.IsAdmin() {
+			WriteJSONError(c, errForbidden, http.StatusForbidden)
+			return
+		}
+		userID = other
+	}
+
+	keys, err := h.DB.GetAPIKeys(c.Request.Context(), auth.Tenant(c), userID)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"api_keys": keys,
+	})
+}
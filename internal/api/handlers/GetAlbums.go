@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+// GetAlbums lists the albums of the client, admins may list the albums of
+// another user with owner.
+func (h *Handler) GetAlbums(c *ginext.Context) {
+	owner := clientUserID(c)
+	if other := c.Query("owner"); other != "" && other != owner {
+		identity, ok := auth.FromContext(c)
+		if !ok || !identity.IsAdmin() {
+			WriteJSONError(c, errForbidden, http.StatusForbidden)
+			return
+		}
+		owner = other
+	}
+
+	albums, err := h.DB.GetAlbums(c.Request.Context(), auth.Tenant(c), owner)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"albums": albums,
+	})
+}
+
+// GetAlbum returns the album with the ids of its images in album order.
+func (h *Handler) GetAlbum(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	album, status, err := h.authorizedAlbum(c, id, false)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
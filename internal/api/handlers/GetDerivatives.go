@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+)
+
+func (h *Handler) GetDerivatives(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	_, status, err := h.authorizedImage(c, id, false)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	derivatives, err := h.DB.GetDerivatives(c.Request.Context(), auth.Tenant(c), id)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	type derivativeWithUrl struct {
+		model.Derivative
+		Url string `json:"url,omitempty"`
+	}
+
+	expiresAt := h.urlExpiresAt()
+	resp := make([]derivativeWithUrl, 0, len(derivatives))
+	for _, d := range derivatives {
+		item := derivativeWithUrl{Derivative: d}
+		if d.Processed {
+			item.Url, err = h.ImageStorage.GetURL(c.Request.Context(), d.ProcessedPath, h.URLExpiry)
+			if err != nil {
+				WriteJSONError(c, err, http.StatusInternalServerError)
+				return
+			}
+		}
+		resp = append(resp, item)
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"derivatives": resp,
+		"expires_at":  expiresAt,
+	})
+}
@@ -1,11 +1,10 @@
 package handlers
 
 import (
-	"database/sql"
-	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/wb-go/wbf/ginext"
 )
@@ -18,15 +17,9 @@ func (h *Handler) GetImage(c *ginext.Context) {
 		return
 	}
 
-	img, err := h.DB.GetImage(c.Request.Context(), id)
+	img, status, err := h.authorizedImage(c, id, false)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, ginext.H{
-				"error": "not found",
-			})
-			return
-		}
-		WriteJSONError(c, err, http.StatusInternalServerError)
+		WriteJSONError(c, err, status)
 		return
 	}
 
@@ -35,15 +28,25 @@ func (h *Handler) GetImage(c *ginext.Context) {
 		return
 	}
 
-	var url string
-	if img.Processed {
-		url = img.ProcessedPath
-	} else {
-		url = img.UploadsPath
+	expiresAt := h.urlExpiresAt()
+	url, err := h.ImageStorage.GetURL(c.Request.Context(), img.ProcessedPath, h.URLExpiry)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp := ginext.H{
+		"url":        url,
+		"expires_at": expiresAt,
 	}
+	if img.Result != nil {
+		resp["result"] = img.Result
+	}
+	c.JSON(http.StatusOK, resp)
 
-	c.JSON(http.StatusOK, ginext.H{
-		"url": "/images/" + url,
-	})
+}
 
+// urlExpiresAt is the time presigned URLs created now stop working.
+func (h *Handler) urlExpiresAt() time.Time {
+	return time.Now().Add(h.URLExpiry).UTC().Truncate(time.Second)
 }
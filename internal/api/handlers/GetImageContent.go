@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+)
+
+// GetImageContent streams the processed image, so clients do not need the
+// proxy in front of the ImageStore.
+func (h *Handler) GetImageContent(c *ginext.Context) {
+	h.serveImage(c, false, func(img model.ImageInRepo) (string, error) {
+		if !img.Processed || img.ProcessedPath == "" {
+			return "", fmt.Errorf("image is not processed yet")
+		}
+		return img.ProcessedPath, nil
+	})
+}
+
+// GetImageOriginal streams the uploaded original. Viewers only get the
+// processed image, the original needs the rights to modify the image.
+func (h *Handler) GetImageOriginal(c *ginext.Context) {
+	h.serveImage(c, true, func(img model.ImageInRepo) (string, error) {
+		return img.UploadsPath, nil
+	})
+}
+
+// serveImage writes the object selected by objectOf, modify is passed to
+// authorizedImage. Range requests and the
+// If-None-Match and If-Modified-Since conditions are handled by
+// http.ServeContent using the ETag and modification time of the object.
+func (h *Handler) serveImage(c *ginext.Context, modify bool, objectOf func(model.ImageInRepo) (string, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	img, status, err := h.authorizedImage(c, id, modify)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	objectName, err := objectOf(img)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusConflict)
+		return
+	}
+
+	file, info, err := h.ImageStorage.Open(ctx, objectName)
+	if err != nil {
+		if errors.Is(err, repository.ErrObjectNotFound) {
+			WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	// Without a specific type ServeContent guesses it from the name and
+	// the first bytes of the object.
+	if info.ContentType != "" && info.ContentType != "application/octet-stream" {
+		c.Header("Content-Type", info.ContentType)
+	}
+	if info.ETag != "" {
+		c.Header("ETag", `"`+strings.Trim(info.ETag, `"`)+`"`)
+	}
+	c.Header("Cache-Control", "no-cache")
+
+	http.ServeContent(c.Writer, c.Request, path.Base(objectName), info.LastModified, file)
+}
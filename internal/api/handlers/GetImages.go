@@ -1,62 +1,106 @@
 package handlers
 
 import (
-	"ImageProcessor/internal/model"
-	"context"
 	"net/http"
-	"strconv"
-	"time"
+	"slices"
 
 	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
 )
 
+// GetImages returns a page of the images the client may see. next_cursor
+// and prev_cursor continue the listing with the same parameters and are
+// empty on the last and the first page.
 func (h *Handler) GetImages(c *ginext.Context) {
-	lastCreatedAtStr := c.Query("last_created_at")
-	lastCreatedAt, err := time.Parse(time.RFC3339, lastCreatedAtStr)
+	filter, back, err := listFilter(c)
 	if err != nil {
 		WriteJSONError(c, err, http.StatusBadRequest)
 		return
 	}
+	if filter.AlbumID != 0 {
+		_, status, err := h.authorizedAlbum(c, filter.AlbumID, false)
+		if err != nil {
+			WriteJSONError(c, err, status)
+			return
+		}
+	}
 
-	lastIDStr := c.DefaultQuery("last_id", "0")
-	lastID, err := strconv.Atoi(lastIDStr)
+	// One more image than the page tells whether the listing goes on, pages
+	// before the cursor are read in the opposite order.
+	ctx := c.Request.Context()
+	page := filter
+	page.Limit++
+	page.Desc = filter.Desc != back
+	images, err := h.DB.GetImages(ctx, auth.Tenant(c), page)
 	if err != nil {
-		WriteJSONError(c, err, http.StatusBadRequest)
+		WriteJSONError(c, err, http.StatusInternalServerError)
 		return
 	}
 
-	mode := c.Query("mode")
-	images, err := h.DB.GetImages(context.Background(), lastCreatedAt, lastID, mode)
+	more := len(images) > filter.Limit
+	if more {
+		images = images[:filter.Limit]
+	}
+	if back {
+		slices.Reverse(images)
+	}
+
+	var nextCursor, prevCursor string
+	if len(images) > 0 {
+		if back || more {
+			nextCursor = encodeCursor(filter, images[len(images)-1], false)
+		}
+		if (back && more) || (!back && filter.After != nil) {
+			prevCursor = encodeCursor(filter, images[0], true)
+		}
+	}
+
+	count, err := h.DB.GetCountImages(ctx, auth.Tenant(c), filter)
 	if err != nil {
 		WriteJSONError(c, err, http.StatusInternalServerError)
 		return
 	}
 
-	count, err := h.DB.GetCountImages(context.Background())
+	// Unprocessed images get no URL, like GetImage, so the listing does not
+	// hand out their originals.
+	objectNames := make([]string, 0, len(images))
+	processed := make([]int, 0, len(images))
+	for i, v := range images {
+		if v.Processed && v.ProcessedPath != "" {
+			objectNames = append(objectNames, v.ProcessedPath)
+			processed = append(processed, i)
+		}
+	}
+
+	expiresAt := h.urlExpiresAt()
+	presigned, err := h.ImageStorage.GetManyURL(ctx, objectNames, h.URLExpiry)
 	if err != nil {
 		WriteJSONError(c, err, http.StatusInternalServerError)
 		return
 	}
+	urls := make([]string, len(images))
+	for j, i := range processed {
+		urls[i] = presigned[j]
+	}
 
-	var imageWithUrl []struct {
+	imageWithUrl := make([]struct {
 		model.ImageInRepo
-		Url string `json:"url"`
-	}
-	var url string
-	for _, v := range images {
-		if v.Processed {
-			url = v.ProcessedPath
-		} else {
-			url = v.UploadsPath
-		}
+		Url string `json:"url,omitempty"`
+	}, 0, len(images))
+	for i, v := range images {
 		imageWithUrl = append(imageWithUrl, struct {
 			model.ImageInRepo
-			Url string `json:"url"`
-		}{v, "/images/" + url})
+			Url string `json:"url,omitempty"`
+		}{v, urls[i]})
 	}
 
 	c.JSON(http.StatusOK, ginext.H{
-		"count":  count,
-		"images": imageWithUrl,
+		"count":       count,
+		"images":      imageWithUrl,
+		"next_cursor": nextCursor,
+		"prev_cursor": prevCursor,
+		"expires_at":  expiresAt,
 	})
 }
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) GetPresets(c *ginext.Context) {
+	c.JSON(http.StatusOK, ginext.H{
+		"default": h.Presets.Default(),
+		"presets": h.Presets.List(),
+	})
+}
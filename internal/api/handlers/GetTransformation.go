@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+func (h *Handler) GetTransformation(c *ginext.Context) {
+	t, err := h.DB.GetTransformation(c.Request.Context(), auth.Tenant(c), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, t)
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+func (h *Handler) GetTransformations(c *ginext.Context) {
+	transformations, err := h.DB.GetTransformations(c.Request.Context(), auth.Tenant(c))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"transformations": transformations,
+	})
+}
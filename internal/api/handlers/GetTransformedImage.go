@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+	"ImageProcessor/internal/service"
+)
+
+// GetTransformedImage serves /t/{signature}/{options}/{image_id}, images of
+// other tenants than the default one add ?tenant=. The variant is built
+// synchronously from the original on the first request and stored under
+// processed/t/ of the tenant, later requests read it from the ImageStore as
+// long as the image itself exists.
+func (h *Handler) GetTransformedImage(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	tenant := c.DefaultQuery("tenant", model.DefaultTenant)
+	options := c.Param("options")
+	err = service.VerifyURL(h.URLSecret, c.Param("signature"), tenant, options, id)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusForbidden)
+		return
+	}
+
+	opts, err := service.ParseURLOptions(options)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	variant := repository.TenantKey(tenant, opts.VariantName(id))
+
+	img, err := h.DB.GetImage(ctx, tenant, id)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+	if img.ID == 0 {
+		WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
+		return
+	}
+
+	task, err := opts.Task(img)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusConflict)
+		return
+	}
+
+	exists, err := h.ImageStorage.Exists(ctx, variant)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	if !exists {
+		status, err := h.buildVariant(ctx, task, variant)
+		if err != nil {
+			WriteJSONError(c, err, status)
+			return
+		}
+	}
+
+	file, info, err := h.ImageStorage.Open(ctx, variant)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	// Variants are stored without an extension, so the type comes from the
+	// object or from the first bytes of it.
+	if info.ContentType != "" && info.ContentType != "application/octet-stream" {
+		c.Header("Content-Type", info.ContentType)
+	}
+	if info.ETag != "" {
+		c.Header("ETag", `"`+strings.Trim(info.ETag, `"`)+`"`)
+	}
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(c.Writer, c.Request, "", info.LastModified, file)
+}
+
+func (h *Handler) buildVariant(ctx context.Context, task model.ImageTask, variant string) (int, error) {
+	_, err := service.ProcessImage(service.ImageService{
+		Ctx:          ctx,
+		ImageStorage: h.ImageStorage,
+		Img:          task,
+		OutputName:   variant,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrBadParameters) {
+			return http.StatusBadRequest, err
+		}
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+)
+
+// ReprocessImage runs another operation on the original of an uploaded image.
+// The result is recorded as a derivative and does not replace the processed
+// image.
+func (h *Handler) ReprocessImage(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	img, status, err := h.authorizedImage(c, id, true)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	typeProcessing := c.PostForm("type_processing")
+
+	task := model.ImageTask{
+		ImageID:        id,
+		Tenant:         auth.Tenant(c),
+		TypeProcessing: typeProcessing,
+		UploadsPath:    img.UploadsPath,
+	}
+
+	err = getParameters(c, typeProcessing, &task, h)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	derivativeID, err := h.DB.CreateDerivative(c.Request.Context(), task.Tenant, model.Derivative{
+		ImageID:        id,
+		TypeProcessing: task.TypeProcessing,
+	})
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+	task.DerivativeID = derivativeID
+
+	err = h.Producer.Publish(context.Background(), task)
+	if err != nil {
+		if err := h.DB.DeleteDerivative(c.Request.Context(), task.Tenant, derivativeID); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+	zlog.Logger.Info().Msg("Message publish")
+
+	c.JSON(http.StatusOK, ginext.H{
+		"result":        "image publish in queue",
+		"image_id":      id,
+		"derivative_id": derivativeID,
+	})
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/resumable"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+)
+
+const (
+	tusVersion        = "1.0.0"
+	offsetOctetStream = "application/offset+octet-stream"
+)
+
+// CreateUpload starts a resumable upload. Upload-Length is the size of the
+// file, Upload-Metadata holds the file name as in tus: "filename <base64>".
+func (h *Handler) CreateUpload(c *ginext.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		WriteJSONError(c, fmt.Errorf("invalid Upload-Length"), http.StatusBadRequest)
+		return
+	}
+	if length > h.Uploads.MaxSize() {
+		WriteJSONError(c, fmt.Errorf("file is larger than %d bytes", h.Uploads.MaxSize()), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	metadata, err := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	filename := metadata["filename"]
+	if err := h.checkUploadFormat(c, filename); err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.Uploads.Create(auth.Tenant(c), clientUserID(c), filename, length)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("Location", "/upload/resumable/"+session.ID)
+	c.Header("Upload-Offset", "0")
+	c.JSON(http.StatusCreated, session)
+}
+
+// GetUploadOffset reports the progress of an upload in Upload-Offset and
+// Upload-Length, GET requests also get the session in the body.
+func (h *Handler) GetUploadOffset(c *ginext.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Cache-Control", "no-store")
+
+	session, err := h.uploadSession(c)
+	if err != nil {
+		writeUploadError(c, err)
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.Length, 10))
+	c.JSON(http.StatusOK, session)
+}
+
+// PatchUpload appends the request body at Upload-Offset, which must match
+// the current offset of the upload.
+func (h *Handler) PatchUpload(c *ginext.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+
+	if c.ContentType() != offsetOctetStream {
+		WriteJSONError(c, fmt.Errorf("content type must be %s", offsetOctetStream), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		WriteJSONError(c, fmt.Errorf("invalid Upload-Offset"), http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.uploadSession(c)
+	if err != nil {
+		writeUploadError(c, err)
+		return
+	}
+
+	session, err := h.Uploads.Append(c.Param("id"), offset, c.Request.Body)
+	if session.ID != "" {
+		c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	}
+	if err != nil {
+		writeUploadError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CompleteUpload stores a fully received upload as an original and queues
+// its processing. The form fields are the same as in UploadImage.
+func (h *Handler) CompleteUpload(c *ginext.Context) {
+	id := c.Param("id")
+
+	session, err := h.uploadSession(c)
+	if err != nil {
+		writeUploadError(c, err)
+		return
+	}
+
+	maxSize, status, err := h.checkQuota(c)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+	if maxSize >= 0 && session.Length > maxSize {
+		WriteJSONError(c, errStorageQuota, http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	file, session, err := h.Uploads.Open(id)
+	if err != nil {
+		writeUploadError(c, err)
+		return
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	objectName := newObjectName(auth.Tenant(c), "uploads", session.Filename)
+	typeProcessing := c.PostForm("type_processing")
+
+	task := model.ImageTask{
+		TypeProcessing: typeProcessing,
+		UploadsPath:    objectName,
+	}
+
+	image, err := newImage(c)
+	if err == nil {
+		err = getParameters(c, typeProcessing, &task, h)
+	}
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = h.ImageStorage.Upload(ctx, file, objectName, session.Length)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	image.Size = session.Length
+	imageID, err := h.createAndPublish(ctx, image, task)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuotaExceeded) {
+			h.deleteObject(c, objectName)
+		}
+		WriteJSONError(c, err, createStatus(err))
+		return
+	}
+
+	if err := h.Uploads.Delete(id); err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"result":      "image publish in queue",
+		"object_name": objectName,
+		"image_id":    imageID,
+	})
+}
+
+// DeleteUpload cancels an upload and removes the received data.
+func (h *Handler) DeleteUpload(c *ginext.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+
+	_, err := h.uploadSession(c)
+	if err == nil {
+		err = h.Uploads.Delete(c.Param("id"))
+	}
+	if err != nil {
+		writeUploadError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// uploadSession returns the session of the id parameter. Sessions of other
+// tenants and users are reported as not found, like sessions that do not
+// exist.
+func (h *Handler) uploadSession(c *ginext.Context) (resumable.Session, error) {
+	session, err := h.Uploads.Get(c.Param("id"))
+	if err != nil {
+		return resumable.Session{}, err
+	}
+	if session.Tenant != auth.Tenant(c) || session.Owner != clientUserID(c) {
+		return resumable.Session{}, resumable.ErrNotFound
+	}
+	return session, nil
+}
+
+func writeUploadError(c *ginext.Context, err error) {
+	switch {
+	case errors.Is(err, resumable.ErrNotFound):
+		WriteJSONError(c, err, http.StatusNotFound)
+	case errors.Is(err, resumable.ErrOffsetMismatch), errors.Is(err, resumable.ErrIncomplete):
+		WriteJSONError(c, err, http.StatusConflict)
+	case errors.Is(err, resumable.ErrTooLarge):
+		WriteJSONError(c, err, http.StatusRequestEntityTooLarge)
+	default:
+		WriteJSONError(c, err, http.StatusInternalServerError)
+	}
+}
+
+// parseUploadMetadata decodes comma separated "key base64value" pairs.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if header == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, fmt.Errorf("invalid Upload-Metadata")
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Upload-Metadata value of %s", key)
+		}
+		metadata[key] = string(decoded)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+func (h *Handler) RevokeAPIKey(c *ginext.Context) {
+	key, status, err := h.authorizedAPIKey(c)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	err = h.DB.RevokeAPIKey(c.Request.Context(), auth.Tenant(c), key.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"result": "api key revoked",
+	})
+}
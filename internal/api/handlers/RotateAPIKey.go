@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+// RotateAPIKey replaces the secret of a key, the old one stops working.
+func (h *Handler) RotateAPIKey(c *ginext.Context) {
+	key, status, err := h.authorizedAPIKey(c)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+	if key.RevokedAt != nil {
+		WriteJSONError(c, fmt.Errorf("api key is revoked"), http.StatusConflict)
+		return
+	}
+
+	secret, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = h.DB.RotateAPIKey(c.Request.Context(), auth.Tenant(c), key.ID, prefix, hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("api key is revoked"), http.StatusConflict)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	key.Prefix = prefix
+	key.LastUsedAt = nil
+	c.JSON(http.StatusOK, ginext.H{
+		"key":     secret,
+		"api_key": key,
+	})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/service"
+)
+
+// TagImage adds a tag to an image the client may change, tags are lower
+// case.
+func (h *Handler) TagImage(c *ginext.Context) {
+	id, tag, ok := h.tagParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.DB.TagImage(c.Request.Context(), auth.Tenant(c), id, tag)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"id":  id,
+		"tag": tag,
+	})
+}
+
+func (h *Handler) UntagImage(c *ginext.Context) {
+	id, tag, ok := h.tagParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.DB.UntagImage(c.Request.Context(), auth.Tenant(c), id, tag)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("image does not have the tag"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"result": "tag removed",
+	})
+}
+
+// tagParams reads the image id and the tag and checks that the client may
+// change the image.
+func (h *Handler) tagParams(c *ginext.Context) (int, string, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return 0, "", false
+	}
+	tag, err := service.NormalizeTag(c.Param("tag"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return 0, "", false
+	}
+
+	_, status, err := h.authorizedImage(c, id, true)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return 0, "", false
+	}
+	return id, tag, true
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+// UpdateTransformation replaces the steps and output options of a stored
+// transformation. The watermark is kept unless a new file is sent. A new
+// watermark gets a new object, the old one stays for the uploads already
+// queued with it.
+func (h *Handler) UpdateTransformation(c *ginext.Context) {
+	t, err := h.DB.GetTransformation(c.Request.Context(), auth.Tenant(c), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	watermark, err := getTransformationFields(c, &t)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	if watermark != nil {
+		err = h.uploadFormFile(watermark, t.WatermarkPath)
+		if err != nil {
+			WriteJSONError(c, err, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	updated, err := h.DB.UpdateTransformation(c.Request.Context(), t)
+	if err != nil {
+		if watermark != nil {
+			h.deleteObject(c, t.WatermarkPath)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
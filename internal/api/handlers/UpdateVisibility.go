@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+)
+
+// UpdateVisibility changes the visibility of an image, only images with an
+// owner can become private.
+func (h *Handler) UpdateVisibility(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	img, status, err := h.authorizedImage(c, id, true)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	visibility := c.PostForm("visibility")
+	switch visibility {
+	case model.VisibilityPrivate:
+		if img.Owner == "" {
+			WriteJSONError(c, fmt.Errorf("images without owner cannot be private"), http.StatusBadRequest)
+			return
+		}
+	case model.VisibilityUnlisted, model.VisibilityPublic:
+	default:
+		WriteJSONError(c, fmt.Errorf("unsupported visibility %q", visibility), http.StatusBadRequest)
+		return
+	}
+
+	err = h.DB.UpdateVisibility(c.Request.Context(), auth.Tenant(c), id, visibility)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			WriteJSONError(c, fmt.Errorf("not found"), http.StatusNotFound)
+			return
+		}
+		WriteJSONError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"id":         id,
+		"visibility": visibility,
+	})
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+	"ImageProcessor/internal/service"
+)
+
+const maxBatchFiles = 100
+
+// batchSpec is the processing of one file in the specs field of a batch.
+// An empty type_processing selects the transformation named by preset.
+type batchSpec struct {
+	TypeProcessing string                 `json:"type_processing"`
+	Preset         string                 `json:"preset,omitempty"`
+	Parameters     model.ProcessingParams `json:"parameters"`
+}
+
+type batchResult struct {
+	File       string `json:"file"`
+	ImageID    int    `json:"image_id,omitempty"`
+	ObjectName string `json:"object_name,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// batchFile is an img file of a batch as it was streamed into the
+// ImageStore. Files rejected while streaming have err set and no object.
+type batchFile struct {
+	filename   string
+	objectName string
+	size       int64
+	err        error
+}
+
+// UploadBatch stores every "img" file of the form and queues its processing.
+// Files share the spec given by the usual form fields unless specs holds a
+// JSON array with one spec per file. A failed file does not stop the others,
+// the response has a result for every file and status 207 when any of them
+// failed.
+func (h *Handler) UploadBatch(c *ginext.Context) {
+	maxSize, status, err := h.checkQuota(c)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	files, status, err := h.streamBatchForm(c, maxSize)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	fail := func(err error) {
+		for _, file := range files {
+			if file.objectName != "" {
+				h.deleteObject(c, file.objectName)
+			}
+		}
+		WriteJSONError(c, err, http.StatusBadRequest)
+	}
+
+	if len(files) == 0 {
+		fail(fmt.Errorf("no files"))
+		return
+	}
+
+	image, err := newImage(c)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	var specs []batchSpec
+	var shared model.ImageTask
+
+	specsStr := c.PostForm("specs")
+	if specsStr != "" {
+		err = json.Unmarshal([]byte(specsStr), &specs)
+		if err != nil {
+			fail(fmt.Errorf("invalid specs: %w", err))
+			return
+		}
+		if len(specs) != len(files) {
+			fail(fmt.Errorf("got %d specs for %d files", len(specs), len(files)))
+			return
+		}
+	} else {
+		shared.TypeProcessing = c.PostForm("type_processing")
+		if shared.TypeProcessing == "watermark" {
+			fail(fmt.Errorf("watermark is not supported in batches, use a transformation"))
+			return
+		}
+		err = getParameters(c, shared.TypeProcessing, &shared, h)
+		if err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	results := make([]batchResult, len(files))
+	failed := 0
+
+	for i, file := range files {
+		err := file.err
+		task := shared
+		if err == nil && specs != nil {
+			task, err = h.taskFromSpec(c, specs[i])
+			if err != nil {
+				h.deleteObject(c, file.objectName)
+			}
+		}
+		if err == nil {
+			results[i], err = h.uploadBatchFile(ctx, file, image, task)
+		}
+
+		results[i].File = file.filename
+		if err != nil {
+			results[i].Error = err.Error()
+			failed++
+		}
+	}
+
+	status = http.StatusOK
+	if failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, ginext.H{
+		"uploaded": len(files) - failed,
+		"failed":   failed,
+		"results":  results,
+	})
+}
+
+// streamBatchForm reads the multipart body part by part like
+// streamUploadForm. Every img file goes straight into the ImageStore, the
+// files of a batch together may have maxSize bytes, a negative maxSize
+// disables the check. A file of an unsupported format or beyond the quota
+// fails on its own, the other files are still stored.
+func (h *Handler) streamBatchForm(c *ginext.Context, maxSize int64) ([]batchFile, int, error) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	values := url.Values{}
+	remaining := int64(maxFormValues)
+	var files []batchFile
+
+	fail := func(status int, err error) ([]batchFile, int, error) {
+		for _, file := range files {
+			if file.objectName != "" {
+				h.deleteObject(c, file.objectName)
+			}
+		}
+		return nil, status, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fail(http.StatusBadRequest, err)
+		}
+
+		switch name := part.FormName(); {
+		case part.FileName() == "":
+			value, err := io.ReadAll(io.LimitReader(part, remaining+1))
+			if err != nil {
+				return fail(http.StatusBadRequest, err)
+			}
+			remaining -= int64(len(value))
+			if remaining < 0 {
+				return fail(http.StatusRequestEntityTooLarge, fmt.Errorf("form fields are too large"))
+			}
+			values.Add(name, string(value))
+
+		case name == "img":
+			if len(files) == maxBatchFiles {
+				return fail(http.StatusBadRequest, fmt.Errorf("too many files, maximum is %d", maxBatchFiles))
+			}
+			file := batchFile{filename: part.FileName()}
+			file.err = h.checkUploadFormat(c, file.filename)
+			if file.err == nil {
+				file.objectName, file.size, file.err = h.streamBatchFile(c, part, maxSize)
+			}
+			if file.err == nil && maxSize >= 0 {
+				maxSize -= file.size
+			}
+			files = append(files, file)
+		}
+	}
+
+	c.Request.Form = values
+	c.Request.PostForm = values
+	c.Request.MultipartForm = &multipart.Form{Value: values}
+	return files, http.StatusOK, nil
+}
+
+func (h *Handler) streamBatchFile(c *ginext.Context, part *multipart.Part, maxSize int64) (string, int64, error) {
+	name := newObjectName(auth.Tenant(c), "uploads", part.FileName())
+	file := &countingReader{r: part, limit: maxSize}
+	err := h.ImageStorage.Upload(c.Request.Context(), file, name, -1)
+	if file.exceeded() {
+		h.deleteObject(c, name)
+		return "", 0, errStorageQuota
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return name, file.n, nil
+}
+
+// uploadBatchFile records a stored file and queues its processing. Results
+// of failed files have no object name, createAndPublish removes the original
+// when the task cannot be queued.
+func (h *Handler) uploadBatchFile(ctx context.Context, file batchFile, image model.ImageInCreate, task model.ImageTask) (batchResult, error) {
+	task.UploadsPath = file.objectName
+	image.Size = file.size
+	id, err := h.createAndPublish(ctx, image, task)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuotaExceeded) {
+			if err := h.ImageStorage.Delete(ctx, file.objectName); err != nil {
+				zlog.Logger.Error().Msg(err.Error())
+			}
+		}
+		return batchResult{}, err
+	}
+	return batchResult{ImageID: id, ObjectName: file.objectName}, nil
+}
+
+func (h *Handler) taskFromSpec(c *ginext.Context, spec batchSpec) (model.ImageTask, error) {
+	if spec.Parameters.WatermarkPath != nil || spec.TypeProcessing == "watermark" {
+		return model.ImageTask{}, fmt.Errorf("watermark is not supported in batches, use a transformation")
+	}
+
+	task := model.ImageTask{
+		TypeProcessing: spec.TypeProcessing,
+		Parameters:     spec.Parameters,
+	}
+
+	switch spec.TypeProcessing {
+	case "":
+		t, err := h.DB.GetTransformation(c.Request.Context(), auth.Tenant(c), spec.Preset)
+		if err != nil {
+			return model.ImageTask{}, fmt.Errorf("unknown preset %q", spec.Preset)
+		}
+		service.ApplyTransformation(&task, t)
+		return task, nil
+	case "thumbnail":
+		preset, err := h.preset(c, spec.Preset)
+		if err != nil {
+			return model.ImageTask{}, err
+		}
+		service.ApplyPreset(&task.Parameters, preset)
+	}
+
+	err := service.ValidateStep(model.Step{TypeProcessing: task.TypeProcessing, Parameters: task.Parameters})
+	if err != nil {
+		if errors.Is(err, service.ErrUnknowMode) {
+			return model.ImageTask{}, fmt.Errorf("unknown type_processing %q", spec.TypeProcessing)
+		}
+		return model.ImageTask{}, fmt.Errorf("invalid %s parameters: %w", spec.TypeProcessing, err)
+	}
+	return task, nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/service"
+)
+
+// UploadFromURL queues an image living on another server. The worker
+// fetches it into uploads/ before processing, the form fields are the same
+// as in UploadImage with url instead of the file. The extension of the URL
+// is checked here, the format of the content by the worker. Imports are
+// rejected when the quota of the client is used up, the worker adds the
+// fetched size to the usage.
+func (h *Handler) UploadFromURL(c *ginext.Context) {
+	sourceURL := c.PostForm("url")
+	err := service.ValidateSourceURL(sourceURL)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	image, err := newImage(c)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	_, status, err := h.checkQuota(c)
+	if err != nil {
+		WriteJSONError(c, err, status)
+		return
+	}
+
+	filename := sourceFilename(sourceURL)
+	if filename != "" {
+		if err := h.checkUploadFormat(c, filename); err != nil {
+			WriteJSONError(c, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	typeProcessing := c.PostForm("type_processing")
+	objectName := newObjectName(image.Tenant, "uploads", filename)
+
+	task := model.ImageTask{
+		TypeProcessing: typeProcessing,
+		UploadsPath:    objectName,
+		SourceURL:      sourceURL,
+	}
+
+	err = getParameters(c, typeProcessing, &task, h)
+	if err != nil {
+		WriteJSONError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.createAndPublish(c.Request.Context(), image, task)
+	if err != nil {
+		WriteJSONError(c, err, createStatus(err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, ginext.H{
+		"result":      "image fetch in queue",
+		"object_name": objectName,
+		"image_id":    id,
+	})
+}
+
+// sourceFilename keeps the extension of the URL path when it is an image
+// extension, the stored name has none otherwise.
+func sourceFilename(sourceURL string) string {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return ""
+	}
+	name := path.Base(u.Path)
+	if !slices.Contains(imageExtensions, path.Ext(name)) {
+		return ""
+	}
+	return name
+}
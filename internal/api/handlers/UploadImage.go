@@ -2,87 +2,215 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/wb-go/wbf/ginext"
 	"github.com/wb-go/wbf/zlog"
 
+	"ImageProcessor/internal/api/auth"
 	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+	"ImageProcessor/internal/service"
+)
+
+var imageExtensions = []string{".png", ".gif", ".jpeg", ".jpg"}
+
+const (
+	maxFormValues        = 1 << 20
+	maxWatermarkSize     = 16 << 20
+	streamedWatermarkKey = "streamed_watermark"
+)
+
+var (
+	errWatermarkFormat = fmt.Errorf("unsupported watermark format")
+	errWatermarkSize   = fmt.Errorf("watermark is larger than %d bytes", maxWatermarkSize)
 )
 
 func (h *Handler) UploadImage(c *ginext.Context) {
-	fileHeader, err := c.FormFile("img")
+	maxSize, status, err := h.checkQuota(c)
 	if err != nil {
-		WriteJSONError(c, err, http.StatusBadRequest)
+		WriteJSONError(c, err, status)
 		return
 	}
 
-	ext := filepath.Ext(fileHeader.Filename)
-	if !slices.Contains([]string{".png", ".gif", ".jpeg", ".jpg"}, ext) {
-		WriteJSONError(c, fmt.Errorf("unsupported format"), http.StatusBadRequest)
+	objectName, size, status, err := h.streamUploadForm(c, maxSize)
+	if err != nil {
+		WriteJSONError(c, err, status)
 		return
 	}
 
-	file, err := fileHeader.Open()
+	typeProcessing := c.PostForm("type_processing")
+
+	task := model.ImageTask{
+		TypeProcessing: typeProcessing,
+		UploadsPath:    objectName,
+	}
+
+	image, err := newImage(c)
+	if err == nil {
+		err = getParameters(c, typeProcessing, &task, h)
+	}
+	watermark := c.GetString(streamedWatermarkKey)
+	if watermark != "" && (err != nil || typeProcessing != "watermark") {
+		h.deleteObject(c, watermark)
+	}
 	if err != nil {
+		h.deleteObject(c, objectName)
 		WriteJSONError(c, err, http.StatusBadRequest)
 		return
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			zlog.Logger.Error().Msg(err.Error())
+
+	image.Size = size
+	id, err := h.createAndPublish(c.Request.Context(), image, task)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuotaExceeded) {
+			h.deleteObject(c, objectName)
 		}
-	}()
+		WriteJSONError(c, err, createStatus(err))
+		return
+	}
 
-	objectName := fmt.Sprintf("uploads/%s%s", uuid.New().String(), ext)
+	c.JSON(http.StatusOK, ginext.H{
+		"result":      "image publish in queue",
+		"object_name": objectName,
+		"image_id":    id,
+	})
+}
 
-	err = h.ImageStorage.Upload(context.Background(), file, objectName, fileHeader.Size)
+// streamUploadForm reads the multipart body part by part. The img file goes
+// straight into the ImageStore with unknown size, as does the watermark
+// file, whose object name is kept under streamedWatermarkKey. The other
+// fields are made available to c.PostForm. An img file of more than maxSize
+// bytes fails with 413, a negative maxSize disables the check. Watermarks
+// are limited to maxWatermarkSize bytes.
+func (h *Handler) streamUploadForm(c *ginext.Context, maxSize int64) (string, int64, int, error) {
+	reader, err := c.Request.MultipartReader()
 	if err != nil {
-		WriteJSONError(c, err, http.StatusInternalServerError)
-		return
+		return "", 0, http.StatusBadRequest, err
 	}
 
-	typeProcessing := c.PostForm("type_processing")
+	ctx := c.Request.Context()
+	values := url.Values{}
+	remaining := int64(maxFormValues)
+	var objectName, watermarkName string
+	var size int64
 
-	task := model.ImageTask{
-		TypeProcessing: typeProcessing,
-		UploadsPath:    objectName,
+	fail := func(status int, err error) (string, int64, int, error) {
+		for _, name := range []string{objectName, watermarkName} {
+			if name != "" {
+				h.deleteObject(c, name)
+			}
+		}
+		return "", 0, status, err
 	}
 
-	err = getParameters(c, typeProcessing, &task, h)
-	if err != nil {
-		WriteJSONError(c, err, http.StatusBadRequest)
-		return
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fail(http.StatusBadRequest, err)
+		}
+
+		switch name := part.FormName(); {
+		case part.FileName() == "":
+			value, err := io.ReadAll(io.LimitReader(part, remaining+1))
+			if err != nil {
+				return fail(http.StatusBadRequest, err)
+			}
+			remaining -= int64(len(value))
+			if remaining < 0 {
+				return fail(http.StatusRequestEntityTooLarge, fmt.Errorf("form fields are too large"))
+			}
+			values.Add(name, string(value))
+
+		case name == "img" && objectName == "":
+			if err := h.checkUploadFormat(c, part.FileName()); err != nil {
+				return fail(http.StatusBadRequest, err)
+			}
+			name := newObjectName(auth.Tenant(c), "uploads", part.FileName())
+			file := &countingReader{r: part, limit: maxSize}
+			err = h.ImageStorage.Upload(ctx, file, name, -1)
+			if file.exceeded() {
+				h.deleteObject(c, name)
+				return fail(http.StatusRequestEntityTooLarge, errStorageQuota)
+			}
+			if err != nil {
+				return fail(http.StatusInternalServerError, err)
+			}
+			objectName, size = name, file.n
+
+		case name == "watermark" && watermarkName == "":
+			if !slices.Contains(imageExtensions, filepath.Ext(part.FileName())) {
+				return fail(http.StatusBadRequest, errWatermarkFormat)
+			}
+			name := newObjectName(auth.Tenant(c), "watermarks", part.FileName())
+			file := &countingReader{r: part, limit: maxWatermarkSize}
+			err = h.ImageStorage.Upload(ctx, file, name, -1)
+			if file.exceeded() {
+				h.deleteObject(c, name)
+				return fail(http.StatusRequestEntityTooLarge, errWatermarkSize)
+			}
+			if err != nil {
+				return fail(http.StatusInternalServerError, err)
+			}
+			watermarkName = name
+		}
 	}
 
-	img := model.ImageInCreate{
-		UploadsPath: objectName,
+	if objectName == "" {
+		return fail(http.StatusBadRequest, http.ErrMissingFile)
 	}
 
-	id, err := h.DB.CreateImage(c.Request.Context(), img)
+	c.Request.Form = values
+	c.Request.PostForm = values
+	c.Request.MultipartForm = &multipart.Form{Value: values}
+	if watermarkName != "" {
+		c.Set(streamedWatermarkKey, watermarkName)
+	}
+	return objectName, size, http.StatusOK, nil
+}
+
+// createAndPublish records the uploaded original and queues its processing.
+// When the task cannot be queued the row and the original are removed again.
+func (h *Handler) createAndPublish(ctx context.Context, img model.ImageInCreate, task model.ImageTask) (int, error) {
+	img.UploadsPath = task.UploadsPath
+	img.Quota = h.Quota
+	task.Tenant = img.Tenant
+
+	id, err := h.DB.CreateImage(ctx, img)
 	if err != nil {
-		WriteJSONError(c, err, http.StatusInternalServerError)
-		return
+		return 0, err
 	}
 	task.ImageID = id
 
 	err = h.Producer.Publish(context.Background(), task)
 	if err != nil {
-		WriteJSONError(c, err, http.StatusInternalServerError)
-		return
+		if err := h.DB.DeleteImage(ctx, img.Tenant, id); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+		if err := h.ImageStorage.Delete(ctx, task.UploadsPath); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+		return 0, err
 	}
 	zlog.Logger.Info().Msg("Message publish")
 
-	c.JSON(http.StatusOK, ginext.H{
-		"result":      "image publish in queue",
-		"object_name": objectName,
-		"image_id":    id,
-	})
+	return id, nil
 }
 
 func getHeigthAndWidth(c *ginext.Context) (int, int, error) {
@@ -97,12 +225,75 @@ func getHeigthAndWidth(c *ginext.Context) (int, int, error) {
 	if err != nil {
 		return 0, 0, err
 	}
+
+	if !service.ValidSize(width, height) {
+		return 0, 0, fmt.Errorf("width and height must be between 1 and %d", service.MaxImageSize)
+	}
 	return height, width, nil
 }
 
+func objectNameFor(fileHeader *multipart.FileHeader, tenant, dir string) string {
+	return newObjectName(tenant, dir, fileHeader.Filename)
+}
+
+// newObjectName returns a unique object name in dir under the object prefix
+// of the tenant.
+func newObjectName(tenant, dir, filename string) string {
+	return repository.TenantKey(tenant, fmt.Sprintf("%s/%s%s", dir, uuid.New().String(), filepath.Ext(filename)))
+}
+
+func (h *Handler) uploadFormFile(fileHeader *multipart.FileHeader, objectName string) error {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	return h.ImageStorage.Upload(context.Background(), file, objectName, fileHeader.Size)
+}
+
 func getParameters(c *ginext.Context, typeProcessing string, task *model.ImageTask, h *Handler) error {
+	if typeProcessing == "" {
+		return getTransformation(c, task, h)
+	}
+
+	interpolation := c.PostForm("interpolation")
+	if interpolation != "" {
+		if !service.ValidInterpolation(interpolation) {
+			return fmt.Errorf("unsupported interpolation")
+		}
+		task.Parameters.Interpolation = &interpolation
+	}
+
+	var err error
+	task.Parameters.Sharpen, err = getFloatField(c, "sharpen")
+	if err != nil {
+		return err
+	}
+	if task.Parameters.Sharpen != nil && *task.Parameters.Sharpen <= 0 {
+		return fmt.Errorf("sharpen must be positive")
+	}
+
+	gravity := c.PostForm("gravity")
+	if gravity != "" {
+		if !service.ValidGravity(gravity) {
+			return fmt.Errorf("unsupported gravity")
+		}
+		task.Parameters.Gravity = &gravity
+	}
+
 	switch typeProcessing {
-	case "resize":
+	case "thumbnail":
+		preset, err := h.preset(c, c.PostForm("preset"))
+		if err != nil {
+			return err
+		}
+		service.ApplyPreset(&task.Parameters, preset)
+	case "resize", "crop":
 		height, width, err := getHeigthAndWidth(c)
 		if err != nil {
 			return err
@@ -111,30 +302,282 @@ func getParameters(c *ginext.Context, typeProcessing string, task *model.ImageTa
 		task.Parameters.Width = &width
 
 	case "watermark":
-		fileHeader, err := c.FormFile("watermark")
+		watermarkObjectName := c.GetString(streamedWatermarkKey)
+		if watermarkObjectName == "" {
+			fileHeader, err := c.FormFile("watermark")
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(imageExtensions, filepath.Ext(fileHeader.Filename)) {
+				return errWatermarkFormat
+			}
+			if fileHeader.Size > maxWatermarkSize {
+				return errWatermarkSize
+			}
+
+			watermarkObjectName = objectNameFor(fileHeader, auth.Tenant(c), "watermarks")
+			err = h.uploadFormFile(fileHeader, watermarkObjectName)
+			if err != nil {
+				return err
+			}
+		}
+
+		task.Parameters.WatermarkPath = &watermarkObjectName
+
+	case "rotate":
+		return getRotateParameters(c, task)
+
+	case "adjust":
+		return getAdjustParameters(c, task)
+
+	case "filter":
+		return getFilterParameters(c, task)
+
+	case "redact":
+		err := json.Unmarshal([]byte(c.PostForm("redactions")), &task.Parameters.Redactions)
+		if err != nil {
+			return fmt.Errorf("invalid redactions: %w", err)
+		}
+		if err := service.ValidateRedactions(task.Parameters.Redactions); err != nil {
+			return fmt.Errorf("invalid redactions")
+		}
+
+	case "trim":
+		return getTrimParameters(c, task)
+
+	case "pad", "border", "round":
+		return getCanvasParameters(c, typeProcessing, task)
+	}
+	return nil
+}
+
+// getTransformation turns an upload without type_processing into a pipeline
+// of the stored transformation named by the preset field.
+func getTransformation(c *ginext.Context, task *model.ImageTask, h *Handler) error {
+	name := c.PostForm("preset")
+	if name == "" {
+		return fmt.Errorf("type_processing or preset required")
+	}
+
+	t, err := h.DB.GetTransformation(c.Request.Context(), auth.Tenant(c), name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("unknown preset")
+		}
+		return err
+	}
+
+	service.ApplyTransformation(task, t)
+	return nil
+}
+
+func getRotateParameters(c *ginext.Context, task *model.ImageTask) error {
+	var err error
+	task.Parameters.Angle, err = getFloatField(c, "angle")
+	if err != nil {
+		return err
+	}
+
+	flip := c.PostForm("flip")
+	if flip != "" {
+		if !service.ValidFlip(flip) {
+			return fmt.Errorf("unsupported flip")
+		}
+		task.Parameters.Flip = &flip
+	}
+
+	if task.Parameters.Angle == nil && task.Parameters.Flip == nil {
+		return fmt.Errorf("angle or flip required")
+	}
+
+	background := c.PostForm("background")
+	if background != "" {
+		if _, err := service.ParseColor(background); err != nil {
+			return fmt.Errorf("invalid background color")
+		}
+		task.Parameters.Background = &background
+	}
+
+	task.Parameters.Expand, err = getBoolField(c, "expand")
+	return err
+}
+
+func getAdjustParameters(c *ginext.Context, task *model.ImageTask) error {
+	boolFields := map[string]**bool{
+		"grayscale": &task.Parameters.Grayscale,
+		"sepia":     &task.Parameters.Sepia,
+		"invert":    &task.Parameters.Invert,
+	}
+	for name, field := range boolFields {
+		v, err := getBoolField(c, name)
 		if err != nil {
 			return err
 		}
+		*field = v
+	}
 
-		file, err := fileHeader.Open()
+	floatFields := map[string]**float64{
+		"brightness": &task.Parameters.Brightness,
+		"contrast":   &task.Parameters.Contrast,
+		"saturation": &task.Parameters.Saturation,
+		"hue":        &task.Parameters.Hue,
+		"gamma":      &task.Parameters.Gamma,
+	}
+	for name, field := range floatFields {
+		v, err := getFloatField(c, name)
 		if err != nil {
 			return err
 		}
-		defer func() {
-			if err := file.Close(); err != nil {
-				zlog.Logger.Error().Msg(err.Error())
+		*field = v
+	}
+
+	if err := service.ValidateAdjust(task.Parameters); err != nil {
+		return fmt.Errorf("invalid adjust parameters")
+	}
+	return nil
+}
+
+func getFilterParameters(c *ginext.Context, task *model.ImageTask) error {
+	filter := c.PostForm("filter")
+	task.Parameters.Filter = &filter
+
+	var err error
+	task.Parameters.Sigma, err = getFloatField(c, "sigma")
+	if err != nil {
+		return err
+	}
+
+	task.Parameters.Amount, err = getFloatField(c, "amount")
+	if err != nil {
+		return err
+	}
+
+	task.Parameters.Radius, err = getIntField(c, "radius")
+	if err != nil {
+		return err
+	}
+
+	kernelStr := c.PostForm("kernel")
+	if kernelStr != "" {
+		for _, v := range strings.Split(kernelStr, ",") {
+			value, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return err
+			}
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return fmt.Errorf("kernel must consist of finite numbers")
 			}
-		}()
+			task.Parameters.Kernel = append(task.Parameters.Kernel, value)
+		}
+	}
+
+	if err := service.ValidateFilter(task.Parameters); err != nil {
+		return fmt.Errorf("invalid filter parameters")
+	}
+	return nil
+}
 
-		ext := filepath.Ext(fileHeader.Filename)
-		watermarkObjectName := fmt.Sprintf("watermarks/%s%s", uuid.New().String(), ext)
+func getTrimParameters(c *ginext.Context, task *model.ImageTask) error {
+	trimColor := c.PostForm("trim_color")
+	if trimColor != "" {
+		task.Parameters.TrimColor = &trimColor
+	}
+
+	var err error
+	task.Parameters.Tolerance, err = getIntField(c, "tolerance")
+	if err != nil {
+		return err
+	}
 
-		err = h.ImageStorage.Upload(context.Background(), file, watermarkObjectName, fileHeader.Size)
+	task.Parameters.Padding, err = getIntField(c, "padding")
+	if err != nil {
+		return err
+	}
+
+	if err := service.ValidateTrim(task.Parameters); err != nil {
+		return fmt.Errorf("invalid trim parameters")
+	}
+	return nil
+}
+
+func getCanvasParameters(c *ginext.Context, typeProcessing string, task *model.ImageTask) error {
+	var err error
+	switch typeProcessing {
+	case "pad":
+		task.Parameters.Padding, err = getIntField(c, "padding")
 		if err != nil {
 			return err
 		}
+		background := c.PostForm("background")
+		if background != "" {
+			task.Parameters.Background = &background
+		}
+		err = service.ValidatePad(task.Parameters)
 
-		task.Parameters.WatermarkPath = &watermarkObjectName
+	case "border":
+		task.Parameters.BorderWidth, err = getIntField(c, "border_width")
+		if err != nil {
+			return err
+		}
+		borderColor := c.PostForm("border_color")
+		if borderColor != "" {
+			task.Parameters.BorderColor = &borderColor
+		}
+		err = service.ValidateBorder(task.Parameters)
+
+	case "round":
+		task.Parameters.CornerRadius, err = getIntField(c, "corner_radius")
+		if err != nil {
+			return err
+		}
+		task.Parameters.Circle, err = getBoolField(c, "circle")
+		if err != nil {
+			return err
+		}
+		err = service.ValidateRound(task.Parameters)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s parameters", typeProcessing)
 	}
 	return nil
 }
+
+func getIntField(c *ginext.Context, name string) (*int, error) {
+	str := c.PostForm(name)
+	if str == "" {
+		return nil, nil
+	}
+	v, err := strconv.Atoi(str)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func getFloatField(c *ginext.Context, name string) (*float64, error) {
+	str := c.PostForm(name)
+	if str == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, fmt.Errorf("%s must be a finite number", name)
+	}
+	return &v, nil
+}
+
+func getBoolField(c *ginext.Context, name string) (*bool, error) {
+	str := c.PostForm(name)
+	if str == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseBool(str)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+)
+
+var errForbidden = fmt.Errorf("forbidden")
+
+// canView reports whether the client may read img. Unlisted and public
+// images are readable by anyone who knows the id.
+func canView(c *ginext.Context, img model.ImageInRepo) bool {
+	if img.Visibility != model.VisibilityPrivate {
+		return true
+	}
+	identity, ok := auth.FromContext(c)
+	return ok && (identity.IsAdmin() || identity.UserID == img.Owner)
+}
+
+// canModify reports whether the client may delete or reprocess img. Images
+// uploaded anonymously have no owner and stay modifiable by anyone unless
+// authentication is required.
+func (h *Handler) canModify(c *ginext.Context, img model.ImageInRepo) bool {
+	return h.canModifyOwned(c, img.Owner)
+}
+
+// canModifyOwned applies the rules of canModify to anything with an owner.
+func (h *Handler) canModifyOwned(c *ginext.Context, owner string) bool {
+	identity, ok := auth.FromContext(c)
+	if ok && identity.IsAdmin() {
+		return true
+	}
+	if owner == "" {
+		return !h.AuthRequired
+	}
+	return ok && identity.UserID == owner
+}
+
+// imageAccess limits listings to the images the client may see.
+func imageAccess(c *ginext.Context) model.ImageAccess {
+	identity, ok := auth.FromContext(c)
+	if !ok {
+		return model.ImageAccess{}
+	}
+	return model.ImageAccess{Owner: identity.UserID, All: identity.IsAdmin()}
+}
+
+// newImage returns the tenant, the owner and the visibility of an upload. Uploads of
+// authenticated clients are private by default, anonymous uploads have no
+// owner and cannot be private.
+func newImage(c *ginext.Context) (model.ImageInCreate, error) {
+	img := model.ImageInCreate{Tenant: auth.Tenant(c), Visibility: c.PostForm("visibility")}
+
+	identity, ok := auth.FromContext(c)
+	if ok {
+		img.Owner = identity.UserID
+	}
+
+	switch img.Visibility {
+	case "":
+		img.Visibility = model.VisibilityPublic
+		if ok {
+			img.Visibility = model.VisibilityPrivate
+		}
+	case model.VisibilityPrivate:
+		if !ok {
+			return model.ImageInCreate{}, fmt.Errorf("private images require authentication")
+		}
+	case model.VisibilityUnlisted, model.VisibilityPublic:
+	default:
+		return model.ImageInCreate{}, fmt.Errorf("unsupported visibility %q", img.Visibility)
+	}
+	return img, nil
+}
+
+// authorizedImage loads the image and checks that the client may read it,
+// or change it when modify is set. Images the client cannot read are
+// reported as not found.
+func (h *Handler) authorizedImage(c *ginext.Context, id int, modify bool) (model.ImageInRepo, int, error) {
+	img, err := h.DB.GetImage(c.Request.Context(), auth.Tenant(c), id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.ImageInRepo{}, http.StatusInternalServerError, err
+	}
+	if img.ID == 0 || !canView(c, img) {
+		return model.ImageInRepo{}, http.StatusNotFound, fmt.Errorf("not found")
+	}
+	if modify && !h.canModify(c, img) {
+		return model.ImageInRepo{}, http.StatusForbidden, errForbidden
+	}
+	return img, http.StatusOK, nil
+}
+
+// authorizedAPIKey loads the key of the id parameter. Keys of other users
+// are reported as not found unless the client is an admin.
+func (h *Handler) authorizedAPIKey(c *ginext.Context) (model.APIKey, int, error) {
+	identity, ok := auth.FromContext(c)
+	if !ok {
+		return model.APIKey{}, http.StatusUnauthorized, fmt.Errorf("authentication required")
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return model.APIKey{}, http.StatusBadRequest, err
+	}
+
+	key, err := h.DB.GetAPIKey(c.Request.Context(), auth.Tenant(c), id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.APIKey{}, http.StatusInternalServerError, err
+	}
+	if key.ID == 0 || (key.UserID != identity.UserID && !identity.IsAdmin()) {
+		return model.APIKey{}, http.StatusNotFound, fmt.Errorf("not found")
+	}
+	return key, http.StatusOK, nil
+}
+
+// authorizedAlbum loads the album and checks that the client may read it,
+// or change it when modify is set. Albums belong to their owner, albums
+// created anonymously are readable by anyone. Albums the client cannot read
+// are reported as not found.
+func (h *Handler) authorizedAlbum(c *ginext.Context, id int, modify bool) (model.Album, int, error) {
+	album, err := h.DB.GetAlbum(c.Request.Context(), auth.Tenant(c), id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.Album{}, http.StatusInternalServerError, err
+	}
+	identity, ok := auth.FromContext(c)
+	canView := album.Owner == "" || (ok && (identity.IsAdmin() || identity.UserID == album.Owner))
+	if album.ID == 0 || !canView {
+		return model.Album{}, http.StatusNotFound, fmt.Errorf("not found")
+	}
+	if modify && !h.canModifyOwned(c, album.Owner) {
+		return model.Album{}, http.StatusForbidden, errForbidden
+	}
+	return album, http.StatusOK, nil
+}
@@ -1,20 +1,39 @@
 package handlers
 
 import (
+	"time"
+
 	"github.com/wb-go/wbf/ginext"
 	"github.com/wb-go/wbf/zlog"
 
+	"ImageProcessor/internal/api/resumable"
+	"ImageProcessor/internal/model"
 	"ImageProcessor/internal/repository"
+	"ImageProcessor/internal/service"
 )
 
 type Handler struct {
 	DB           repository.Storager
 	Producer     repository.ImageTaskProducer
 	ImageStorage repository.ImageStore
+	Presets      *service.Presets
+	URLSecret    string
+	Uploads      *resumable.Store
+	URLExpiry    time.Duration
+	// AuthRequired disables changes of images without owner.
+	AuthRequired bool
+	// Tenants limits the upload formats and sets the default preset per
+	// tenant, nil allows every format.
+	Tenants *service.Tenants
+	// Quota limits the originals stored per owner, the zero value disables
+	// the limits and only tracks the usage.
+	Quota model.Quota
 }
 
-func NewHandler(db repository.Storager, p repository.ImageTaskProducer, i repository.ImageStore) *Handler {
-	return &Handler{DB: db, Producer: p, ImageStorage: i}
+const defaultURLExpiry = time.Hour
+
+func NewHandler(db repository.Storager, p repository.ImageTaskProducer, i repository.ImageStore, presets *service.Presets, urlSecret string) *Handler {
+	return &Handler{DB: db, Producer: p, ImageStorage: i, Presets: presets, URLSecret: urlSecret, URLExpiry: defaultURLExpiry}
 }
 
 func WriteJSONError(c *ginext.Context, err error, status int) {
@@ -0,0 +1,149 @@
+package imagetest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+var writer = &auth.Identity{UserID: "alice", Scopes: []string{auth.ScopeUpload, auth.ScopeRead}}
+
+func TestCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		identity       *auth.Identity
+		form           url.Values
+		expectedUser   string
+		expectedStatus int
+	}{
+		{name: "own scopes", identity: writer, form: url.Values{"name": {"ci"}, "scopes": {"upload, read"}}, expectedUser: "alice", expectedStatus: http.StatusCreated},
+		{name: "scope not held", identity: writer, form: url.Values{"name": {"ci"}, "scopes": {"delete"}}, expectedStatus: http.StatusForbidden},
+		{name: "unknown scope", identity: writer, form: url.Values{"name": {"ci"}, "scopes": {"write"}}, expectedStatus: http.StatusBadRequest},
+		{name: "no scopes", identity: writer, form: url.Values{"name": {"ci"}}, expectedStatus: http.StatusBadRequest},
+		{name: "no name", identity: writer, form: url.Values{"scopes": {"read"}}, expectedStatus: http.StatusBadRequest},
+		{name: "key of other user", identity: writer, form: url.Values{"name": {"ci"}, "scopes": {"read"}, "user_id": {"bob"}}, expectedStatus: http.StatusForbidden},
+		{name: "admin creates for other user", identity: admin, form: url.Values{"name": {"ci"}, "scopes": {"admin"}, "user_id": {"bob"}}, expectedUser: "bob", expectedStatus: http.StatusCreated},
+		{name: "anonymous", form: url.Values{"name": {"ci"}, "scopes": {"read"}}, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			if tt.expectedStatus == http.StatusCreated {
+				mockDB.EXPECT().CreateAPIKey(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, key model.APIKey) (model.APIKey, error) {
+					key.ID = 1
+					return key, nil
+				}).Once()
+			}
+
+			h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+
+			req := httptest.NewRequest("POST", "/keys", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rr := httptest.NewRecorder()
+			h.CreateAPIKey(newIdentityContext(rr, req, tt.identity, ""))
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var resp struct {
+				Key    string `json:"key"`
+				APIKey struct {
+					UserID  string `json:"user_id"`
+					Prefix  string `json:"prefix"`
+					KeyHash string `json:"key_hash"`
+				} `json:"api_key"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tt.expectedUser, resp.APIKey.UserID)
+			require.True(t, strings.HasPrefix(resp.Key, "ipk_"+resp.APIKey.Prefix+"_"))
+			require.Empty(t, resp.APIKey.KeyHash)
+		})
+	}
+}
+
+func TestManageAPIKey(t *testing.T) {
+	revokedAt := time.Now()
+	own := model.APIKey{ID: 5, UserID: "alice", Prefix: "0123456789ab", Scopes: []string{auth.ScopeRead}}
+	revoked := own
+	revoked.RevokedAt = &revokedAt
+
+	tests := []struct {
+		name           string
+		rotate         bool
+		identity       *auth.Identity
+		key            model.APIKey
+		keyErr         error
+		expectedStatus int
+	}{
+		{name: "revoke own", identity: writer, key: own, expectedStatus: http.StatusOK},
+		{name: "admin revokes", identity: admin, key: own, expectedStatus: http.StatusOK},
+		{name: "revoke of other user", identity: bob, key: own, expectedStatus: http.StatusNotFound},
+		{name: "revoke missing", identity: writer, keyErr: sql.ErrNoRows, expectedStatus: http.StatusNotFound},
+		{name: "rotate own", rotate: true, identity: writer, key: own, expectedStatus: http.StatusOK},
+		{name: "rotate revoked", rotate: true, identity: writer, key: revoked, expectedStatus: http.StatusConflict},
+		{name: "rotate of other user", rotate: true, identity: bob, key: own, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockDB.On("GetAPIKey", mock.Anything, "default", 5).Return(tt.key, tt.keyErr).Once()
+			if tt.expectedStatus == http.StatusOK {
+				if tt.rotate {
+					mockDB.On("RotateAPIKey", mock.Anything, "default", 5, mock.Anything, mock.Anything).Return(nil).Once()
+				} else {
+					mockDB.On("RevokeAPIKey", mock.Anything, "default", 5).Return(nil).Once()
+				}
+			}
+
+			h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+
+			rr := httptest.NewRecorder()
+			if tt.rotate {
+				c := newIdentityContext(rr, httptest.NewRequest("POST", "/keys/5/rotate", nil), tt.identity, "5")
+				h.RotateAPIKey(c)
+			} else {
+				c := newIdentityContext(rr, httptest.NewRequest("DELETE", "/keys/5", nil), tt.identity, "5")
+				h.RevokeAPIKey(c)
+			}
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.rotate && tt.expectedStatus == http.StatusOK {
+				require.Contains(t, rr.Body.String(), `"key":"ipk_`)
+			}
+		})
+	}
+}
+
+func TestGetAPIKeys(t *testing.T) {
+	mockDB := mocks.NewMockStorager(t)
+	mockDB.On("GetAPIKeys", mock.Anything, "default", "alice").Return([]model.APIKey{{ID: 5, UserID: "alice", KeyHash: "secret-hash"}}, nil).Once()
+
+	h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+
+	rr := httptest.NewRecorder()
+	h.GetAPIKeys(newIdentityContext(rr, httptest.NewRequest("GET", "/keys", nil), writer, ""))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotContains(t, rr.Body.String(), "secret-hash")
+
+	rr = httptest.NewRecorder()
+	h.GetAPIKeys(newIdentityContext(rr, httptest.NewRequest("GET", "/keys?user_id=bob", nil), writer, ""))
+	require.Equal(t, http.StatusForbidden, rr.Code)
+}
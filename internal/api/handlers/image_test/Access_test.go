@@ -0,0 +1,178 @@
+package imagetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+var (
+	alice = &auth.Identity{UserID: "alice"}
+	bob   = &auth.Identity{UserID: "bob"}
+	admin = &auth.Identity{UserID: "root", Scopes: []string{auth.ScopeAdmin}}
+)
+
+func newIdentityContext(rr *httptest.ResponseRecorder, req *http.Request, identity *auth.Identity, id string) *gin.Context {
+	c, _ := gin.CreateTestContext(rr)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: id}}
+	if identity != nil {
+		auth.SetIdentity(c, *identity)
+	}
+	return c
+}
+
+func TestImageAccess(t *testing.T) {
+	private := model.ImageInRepo{ID: 9, UploadsPath: "uploads/9.png", ProcessedPath: "processed/9.png", Processed: true, Owner: "alice", Visibility: model.VisibilityPrivate}
+	unlisted := private
+	unlisted.Visibility = model.VisibilityUnlisted
+	ownerless := private
+	ownerless.Owner = ""
+	ownerless.Visibility = model.VisibilityPublic
+
+	tests := []struct {
+		name           string
+		img            model.ImageInRepo
+		identity       *auth.Identity
+		authRequired   bool
+		delete         bool
+		expectedStatus int
+	}{
+		{name: "owner reads private", img: private, identity: alice, expectedStatus: http.StatusOK},
+		{name: "admin reads private", img: private, identity: admin, expectedStatus: http.StatusOK},
+		{name: "other user reads private", img: private, identity: bob, expectedStatus: http.StatusNotFound},
+		{name: "anonymous reads private", img: private, expectedStatus: http.StatusNotFound},
+		{name: "anonymous reads unlisted", img: unlisted, expectedStatus: http.StatusOK},
+		{name: "owner deletes", img: private, identity: alice, delete: true, expectedStatus: http.StatusOK},
+		{name: "admin deletes", img: private, identity: admin, delete: true, expectedStatus: http.StatusOK},
+		{name: "other user deletes unlisted", img: unlisted, identity: bob, delete: true, expectedStatus: http.StatusForbidden},
+		{name: "other user deletes private", img: private, identity: bob, delete: true, expectedStatus: http.StatusNotFound},
+		{name: "anonymous deletes ownerless", img: ownerless, delete: true, expectedStatus: http.StatusOK},
+		{name: "ownerless with required auth", img: ownerless, identity: bob, authRequired: true, delete: true, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockImageStorage := mocks.NewMockImageStore(t)
+
+			mockDB.On("GetImage", mock.Anything, "default", 9).Return(tt.img, nil).Once()
+			if tt.expectedStatus == http.StatusOK {
+				if tt.delete {
+					mockDB.On("GetDerivatives", mock.Anything, "default", 9).Return([]model.Derivative{}, nil).Once()
+					mockDB.On("DeleteImage", mock.Anything, "default", 9).Return(nil).Once()
+					mockImageStorage.On("Delete", mock.Anything, mock.Anything).Return(nil).Twice()
+					mockImageStorage.On("DeletePrefix", mock.Anything, "processed/t/9/").Return(nil).Once()
+				} else {
+					mockImageStorage.On("GetURL", mock.Anything, "processed/9.png", mock.Anything).Return("http://minio/9.png", nil).Once()
+				}
+			}
+
+			h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+			h.AuthRequired = tt.authRequired
+
+			rr := httptest.NewRecorder()
+			method := "GET"
+			if tt.delete {
+				method = "DELETE"
+			}
+			c := newIdentityContext(rr, httptest.NewRequest(method, "/image/9", nil), tt.identity, "9")
+
+			if tt.delete {
+				h.DeleteImage(c)
+			} else {
+				h.GetImage(c)
+			}
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestImagesListedByAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		access   model.ImageAccess
+	}{
+		{name: "anonymous", access: model.ImageAccess{}},
+		{name: "user", identity: alice, access: model.ImageAccess{Owner: "alice"}},
+		{name: "admin", identity: admin, access: model.ImageAccess{Owner: "root", All: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockImageStorage := mocks.NewMockImageStore(t)
+
+			hasAccess := mock.MatchedBy(func(f model.ImageFilter) bool { return f.Access == tt.access })
+			mockDB.On("GetImages", mock.Anything, "default", hasAccess).Return([]model.ImageInRepo{}, nil).Once()
+			mockDB.On("GetCountImages", mock.Anything, "default", hasAccess).Return(0, nil).Once()
+			mockImageStorage.On("GetManyURL", mock.Anything, []string{}, mock.Anything).Return([]string{}, nil).Once()
+
+			h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/images", nil)
+			c := newIdentityContext(rr, req, tt.identity, "")
+
+			h.GetImages(c)
+			require.Equal(t, http.StatusOK, rr.Code)
+		})
+	}
+}
+
+func TestUploadVisibility(t *testing.T) {
+	tests := []struct {
+		name           string
+		identity       *auth.Identity
+		visibility     string
+		expected       model.ImageInCreate
+		expectedStatus int
+	}{
+		{name: "user default", identity: alice, expected: model.ImageInCreate{Owner: "alice", Visibility: model.VisibilityPrivate}, expectedStatus: http.StatusAccepted},
+		{name: "user public", identity: alice, visibility: "public", expected: model.ImageInCreate{Owner: "alice", Visibility: model.VisibilityPublic}, expectedStatus: http.StatusAccepted},
+		{name: "anonymous default", expected: model.ImageInCreate{Visibility: model.VisibilityPublic}, expectedStatus: http.StatusAccepted},
+		{name: "anonymous private", visibility: "private", expectedStatus: http.StatusBadRequest},
+		{name: "unknown visibility", identity: alice, visibility: "secret", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockProducer := mocks.NewMockImageTaskProducer(t)
+			if tt.expectedStatus == http.StatusAccepted {
+				mockDB.On("CreateImage", mock.Anything, mock.MatchedBy(func(img model.ImageInCreate) bool {
+					return img.Owner == tt.expected.Owner && img.Visibility == tt.expected.Visibility
+				})).Return(1, nil).Once()
+				mockProducer.On("Publish", mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			h := handlers.NewHandler(mockDB, mockProducer, mocks.NewMockImageStore(t), newTestPresets(t), "")
+
+			form := url.Values{"url": {"https://example.com/cat.jpg"}, "type_processing": {"thumbnail"}}
+			if tt.visibility != "" {
+				form.Set("visibility", tt.visibility)
+			}
+			req := httptest.NewRequest("POST", "/upload/url", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			rr := httptest.NewRecorder()
+			c := newIdentityContext(rr, req, tt.identity, "")
+
+			h.UploadFromURL(c)
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package imagetest
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func newParamsContext(rr *httptest.ResponseRecorder, req *http.Request, identity *auth.Identity, params gin.Params) *gin.Context {
+	c := newIdentityContext(rr, req, identity, "")
+	c.Params = params
+	return c
+}
+
+func TestCreateAlbum(t *testing.T) {
+	t.Run("owned by the client", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockDB.On("CreateAlbum", mock.Anything, model.Album{Tenant: "default", Owner: "alice", Name: "Summer sale"}).
+			Return(model.Album{ID: 3, Tenant: "default", Owner: "alice", Name: "Summer sale"}, nil).Once()
+
+		h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+		req := httptest.NewRequest("POST", "/albums", strings.NewReader(url.Values{"name": {" Summer sale "}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		h.CreateAlbum(newIdentityContext(rr, req, alice, ""))
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.Contains(t, rr.Body.String(), `"id":3`)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		h := handlers.NewHandler(mocks.NewMockStorager(t), nil, nil, nil, "")
+		rr := httptest.NewRecorder()
+		h.CreateAlbum(newIdentityContext(rr, httptest.NewRequest("POST", "/albums", nil), alice, ""))
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestAlbumImages(t *testing.T) {
+	params := gin.Params{{Key: "id", Value: "3"}, {Key: "image_id", Value: "1"}}
+
+	tests := []struct {
+		name           string
+		identity       *auth.Identity
+		albumOwner     string
+		expectAdd      bool
+		expectedStatus int
+	}{
+		{name: "own album", identity: alice, albumOwner: "alice", expectAdd: true, expectedStatus: http.StatusOK},
+		{name: "admin", identity: admin, albumOwner: "alice", expectAdd: true, expectedStatus: http.StatusOK},
+		{name: "album of another user", identity: bob, albumOwner: "alice", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockDB.On("GetAlbum", mock.Anything, "default", 3).Return(model.Album{ID: 3, Owner: tt.albumOwner}, nil).Once()
+			if tt.expectAdd {
+				mockDB.On("GetImage", mock.Anything, "default", 1).Return(model.ImageInRepo{ID: 1, Visibility: model.VisibilityPublic}, nil).Once()
+				mockDB.On("AddAlbumImage", mock.Anything, "default", 3, 1).Return(nil).Once()
+			}
+
+			h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+			rr := httptest.NewRecorder()
+			h.AddAlbumImage(newParamsContext(rr, httptest.NewRequest("PUT", "/albums/3/images/1", nil), tt.identity, params))
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+
+	t.Run("private image of another user", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockDB.On("GetAlbum", mock.Anything, "default", 3).Return(model.Album{ID: 3, Owner: "alice"}, nil).Once()
+		mockDB.On("GetImage", mock.Anything, "default", 1).Return(model.ImageInRepo{ID: 1, Owner: "bob", Visibility: model.VisibilityPrivate}, nil).Once()
+
+		h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+		rr := httptest.NewRecorder()
+		h.AddAlbumImage(newParamsContext(rr, httptest.NewRequest("PUT", "/albums/3/images/1", nil), alice, params))
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("remove image not in the album", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockDB.On("GetAlbum", mock.Anything, "default", 3).Return(model.Album{ID: 3, Owner: "alice"}, nil).Once()
+		mockDB.On("RemoveAlbumImage", mock.Anything, "default", 3, 1).Return(sql.ErrNoRows).Once()
+
+		h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+		rr := httptest.NewRecorder()
+		h.RemoveAlbumImage(newParamsContext(rr, httptest.NewRequest("DELETE", "/albums/3/images/1", nil), alice, params))
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestGetAlbums(t *testing.T) {
+	mockDB := mocks.NewMockStorager(t)
+	mockDB.On("GetAlbums", mock.Anything, "default", "bob").Return([]model.Album{}, nil).Once()
+	h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+
+	rr := httptest.NewRecorder()
+	h.GetAlbums(newIdentityContext(rr, httptest.NewRequest("GET", "/albums?owner=bob", nil), alice, ""))
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.GetAlbums(newIdentityContext(rr, httptest.NewRequest("GET", "/albums?owner=bob", nil), admin, ""))
+	require.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestGetImagesByTagAndAlbum(t *testing.T) {
+	mockDB := mocks.NewMockStorager(t)
+	mockImageStorage := mocks.NewMockImageStore(t)
+
+	byTagAndAlbum := mock.MatchedBy(func(f model.ImageFilter) bool { return f.Tag == "sale" && f.AlbumID == 3 })
+	mockDB.On("GetAlbum", mock.Anything, "default", 3).Return(model.Album{ID: 3, Owner: "alice"}, nil).Once()
+	mockDB.On("GetImages", mock.Anything, "default", byTagAndAlbum).Return([]model.ImageInRepo{}, nil).Once()
+	mockDB.On("GetCountImages", mock.Anything, "default", byTagAndAlbum).Return(0, nil).Once()
+	mockImageStorage.On("GetManyURL", mock.Anything, []string{}, mock.Anything).Return([]string{}, nil).Once()
+
+	h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+
+	rr := httptest.NewRecorder()
+	h.GetImages(newIdentityContext(rr, httptest.NewRequest("GET", "/images?tag=Sale&album=3", nil), alice, ""))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	mockDB.On("GetAlbum", mock.Anything, "default", 3).Return(model.Album{ID: 3, Owner: "alice"}, nil).Once()
+	rr = httptest.NewRecorder()
+	h.GetImages(newIdentityContext(rr, httptest.NewRequest("GET", "/images?album=3", nil), bob, ""))
+	require.Equal(t, http.StatusNotFound, rr.Code, "album of another user")
+}
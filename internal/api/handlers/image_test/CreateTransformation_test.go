@@ -0,0 +1,149 @@
+package imagetest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func createTransformationRequest(t *testing.T, fields map[string]string, watermarkPath string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for key, value := range fields {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+
+	if watermarkPath != "" {
+		file, err := os.Open(watermarkPath)
+		require.NoError(t, err)
+		defer file.Close()
+
+		part, err := writer.CreateFormFile("watermark", filepath.Base(file.Name()))
+		require.NoError(t, err)
+		_, err = io.Copy(part, file)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/transformations", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestCreateTransformation(t *testing.T) {
+	productCard := `[{"type_processing":"thumbnail","parameters":{"width":400,"height":300,"resize_mode":"fill","sharpen":0.5}},{"type_processing":"watermark"}]`
+
+	tests := []struct {
+		name           string
+		fields         map[string]string
+		watermarkPath  string
+		setupMock      func(*mocks.MockStorager, *mocks.MockImageStore)
+		expectedStatus int
+	}{
+		{
+			name: "create with watermark",
+			fields: map[string]string{
+				"name":    "product-card",
+				"steps":   productCard,
+				"format":  "jpeg",
+				"quality": "80",
+			},
+			watermarkPath: testWatermarkPath,
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(func(name string) bool {
+					return filepath.Dir(name) == "transformations"
+				}), mock.Anything).Return(nil).Once()
+				db.EXPECT().CreateTransformation(mock.Anything, mock.MatchedBy(func(tr model.Transformation) bool {
+					return tr.Name == "product-card" && len(tr.Steps) == 2 && tr.Format == "jpeg" &&
+						tr.Quality == 80 && tr.WatermarkPath != ""
+				})).RunAndReturn(func(_ context.Context, tr model.Transformation) (model.Transformation, error) {
+					return tr, nil
+				}).Once()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "watermark step without watermark",
+			fields: map[string]string{
+				"name":  "product-card",
+				"steps": productCard,
+			},
+			setupMock:      func(db *mocks.MockStorager, is *mocks.MockImageStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "watermark path in a step",
+			fields: map[string]string{
+				"name":  "steal",
+				"steps": `[{"type_processing":"watermark","parameters":{"watermark_path":"tenants/other/uploads/secret.png"}}]`,
+			},
+			watermarkPath:  testWatermarkPath,
+			setupMock:      func(db *mocks.MockStorager, is *mocks.MockImageStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown step",
+			fields: map[string]string{
+				"name":  "blurry",
+				"steps": `[{"type_processing":"blur"}]`,
+			},
+			setupMock:      func(db *mocks.MockStorager, is *mocks.MockImageStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid name",
+			fields: map[string]string{
+				"name":  "Product Card",
+				"steps": `[{"type_processing":"resize","parameters":{"width":10,"height":10}}]`,
+			},
+			setupMock:      func(db *mocks.MockStorager, is *mocks.MockImageStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "already exists",
+			fields: map[string]string{
+				"name":  "product-card",
+				"steps": productCard,
+			},
+			watermarkPath: testWatermarkPath,
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				db.On("CreateTransformation", mock.Anything, mock.Anything).Return(model.Transformation{}, repository.ErrAlreadyExists).Once()
+				is.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockImageStorage := mocks.NewMockImageStore(t)
+			tt.setupMock(mockDB, mockImageStorage)
+
+			h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request = createTransformationRequest(t, tt.fields, tt.watermarkPath)
+
+			h.CreateTransformation(c)
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package imagetest
 
 import (
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,21 +13,34 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
 	"ImageProcessor/internal/repository/mocks"
 )
 
 func TestDeleteImage(t *testing.T) {
 	tests := []struct {
 		name           string
-		setupMock      func(*mocks.MockStorager, int)
+		setupMock      func(*mocks.MockStorager, *mocks.MockImageStore, int)
 		id             string
 		expectedStatus int
 		expectedData   map[string]string
 	}{
 		{
 			name: "delete image success",
-			setupMock: func(db *mocks.MockStorager, id int) {
-				db.On("DeleteImage", mock.Anything, id).Return(nil).Once()
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore, id int) {
+				db.On("GetImage", mock.Anything, "default", id).Return(model.ImageInRepo{
+					ID: id, Visibility: model.VisibilityPublic,
+					UploadsPath: "uploads/10.png", ProcessedPath: "processed/resize/10.png",
+				}, nil).Once()
+				db.On("GetDerivatives", mock.Anything, "default", id).Return([]model.Derivative{
+					{ID: 1, ImageID: id, ProcessedPath: "processed/derivatives/1.png", Processed: true},
+					{ID: 2, ImageID: id},
+				}, nil).Once()
+				db.On("DeleteImage", mock.Anything, "default", id).Return(nil).Once()
+				is.On("Delete", mock.Anything, "uploads/10.png").Return(nil).Once()
+				is.On("Delete", mock.Anything, "processed/resize/10.png").Return(nil).Once()
+				is.On("Delete", mock.Anything, "processed/derivatives/1.png").Return(nil).Once()
+				is.On("DeletePrefix", mock.Anything, "processed/t/10/").Return(nil).Once()
 			},
 			id:             "10",
 			expectedStatus: http.StatusOK,
@@ -38,8 +50,8 @@ func TestDeleteImage(t *testing.T) {
 		},
 		{
 			name: "delete image not found",
-			setupMock: func(db *mocks.MockStorager, id int) {
-				db.On("DeleteImage", mock.Anything, id).Return(sql.ErrNoRows).Once()
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore, id int) {
+				db.On("GetImage", mock.Anything, "default", id).Return(model.ImageInRepo{}, nil).Once()
 			},
 			id:             "10",
 			expectedStatus: http.StatusNotFound,
@@ -57,9 +69,9 @@ func TestDeleteImage(t *testing.T) {
 			id, err := strconv.Atoi(tt.id)
 			require.NoError(t, err)
 
-			tt.setupMock(mockDB, id)
+			tt.setupMock(mockDB, mockImageService, id)
 
-			h := handlers.NewHandler(mockDB, nil, mockImageService)
+			h := handlers.NewHandler(mockDB, nil, mockImageService, nil, "")
 
 			rr := httptest.NewRecorder()
 			g, _ := gin.CreateTestContext(rr)
@@ -0,0 +1,62 @@
+package imagetest
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func TestDeleteTransformation(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStorager, *mocks.MockImageStore)
+		expectedStatus int
+	}{
+		{
+			name: "delete with watermark",
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore) {
+				db.On("GetTransformation", mock.Anything, "default", "product-card").Return(model.Transformation{
+					Name:          "product-card",
+					WatermarkPath: "transformations/logo.png",
+				}, nil).Once()
+				db.On("DeleteTransformation", mock.Anything, "default", "product-card").Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore) {
+				db.On("GetTransformation", mock.Anything, "default", "product-card").Return(model.Transformation{}, sql.ErrNoRows).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockImageStorage := mocks.NewMockImageStore(t)
+			tt.setupMock(mockDB, mockImageStorage)
+
+			h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request = httptest.NewRequest("DELETE", "/transformations/product-card", nil)
+			c.Params = gin.Params{
+				gin.Param{Key: "name", Value: "product-card"},
+			}
+
+			h.DeleteTransformation(c)
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
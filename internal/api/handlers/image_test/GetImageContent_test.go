@@ -0,0 +1,194 @@
+package imagetest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+type seekCloser struct {
+	*bytes.Reader
+}
+
+func (seekCloser) Close() error {
+	return nil
+}
+
+func TestGetImageContent(t *testing.T) {
+	data := []byte("0123456789abcdef")
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	info := model.ObjectInfo{Size: int64(len(data)), ContentType: "image/png", ETag: "abc123", LastModified: modified}
+	processed := model.ImageInRepo{ID: 4, UploadsPath: "uploads/4.jpg", ProcessedPath: "processed/4.png", Processed: true}
+
+	tests := []struct {
+		name           string
+		original       bool
+		identity       *auth.Identity
+		headers        map[string]string
+		img            model.ImageInRepo
+		object         string
+		openErr        error
+		expectedStatus int
+		expectedBody   string
+		expectedHeader map[string]string
+	}{
+		{
+			name:           "whole image",
+			img:            processed,
+			object:         "processed/4.png",
+			expectedStatus: http.StatusOK,
+			expectedBody:   string(data),
+			expectedHeader: map[string]string{
+				"Content-Type":   "image/png",
+				"Content-Length": "16",
+				"ETag":           `"abc123"`,
+				"Last-Modified":  modified.Format(http.TimeFormat),
+				"Accept-Ranges":  "bytes",
+			},
+		},
+		{
+			name:           "range",
+			img:            processed,
+			object:         "processed/4.png",
+			headers:        map[string]string{"Range": "bytes=4-7"},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "4567",
+			expectedHeader: map[string]string{"Content-Range": "bytes 4-7/16"},
+		},
+		{
+			name:           "unsatisfiable range",
+			img:            processed,
+			object:         "processed/4.png",
+			headers:        map[string]string{"Range": "bytes=100-"},
+			expectedStatus: http.StatusRequestedRangeNotSatisfiable,
+		},
+		{
+			name:           "if none match",
+			img:            processed,
+			object:         "processed/4.png",
+			headers:        map[string]string{"If-None-Match": `"abc123"`},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "if modified since",
+			img:            processed,
+			object:         "processed/4.png",
+			headers:        map[string]string{"If-Modified-Since": modified.Add(time.Hour).Format(http.TimeFormat)},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "modified after",
+			img:            processed,
+			object:         "processed/4.png",
+			headers:        map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   string(data),
+		},
+		{
+			name:           "original",
+			original:       true,
+			img:            model.ImageInRepo{ID: 4, UploadsPath: "uploads/4.jpg"},
+			object:         "uploads/4.jpg",
+			expectedStatus: http.StatusOK,
+			expectedBody:   string(data),
+		},
+		{
+			name:           "original of another owner",
+			original:       true,
+			identity:       bob,
+			img:            model.ImageInRepo{ID: 4, UploadsPath: "uploads/4.jpg", Owner: "alice", Visibility: model.VisibilityPublic},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "original by the owner",
+			original:       true,
+			identity:       alice,
+			img:            model.ImageInRepo{ID: 4, UploadsPath: "uploads/4.jpg", Owner: "alice", Visibility: model.VisibilityPublic},
+			object:         "uploads/4.jpg",
+			expectedStatus: http.StatusOK,
+			expectedBody:   string(data),
+		},
+		{
+			name:           "anonymous original of an owned image",
+			original:       true,
+			img:            model.ImageInRepo{ID: 4, UploadsPath: "uploads/4.jpg", Owner: "alice", Visibility: model.VisibilityUnlisted},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "not processed",
+			img:            model.ImageInRepo{ID: 4, UploadsPath: "uploads/4.jpg"},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "image not found",
+			img:            model.ImageInRepo{},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "object not found",
+			img:            processed,
+			object:         "processed/4.png",
+			openErr:        repository.ErrObjectNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockImageStorage := mocks.NewMockImageStore(t)
+
+			mockDB.On("GetImage", mock.Anything, "default", 4).Return(tt.img, nil).Once()
+			if tt.object != "" {
+				if tt.openErr != nil {
+					mockImageStorage.On("Open", mock.Anything, tt.object).Return(nil, model.ObjectInfo{}, tt.openErr).Once()
+				} else {
+					mockImageStorage.On("Open", mock.Anything, tt.object).Return(seekCloser{bytes.NewReader(data)}, info, nil).Once()
+				}
+			}
+
+			h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.identity != nil {
+					auth.SetIdentity(c, *tt.identity)
+				}
+			})
+			r.GET("/image/:id/content", h.GetImageContent)
+			r.GET("/image/:id/original", h.GetImageOriginal)
+
+			target := "/image/4/content"
+			if tt.original {
+				target = "/image/4/original"
+			}
+			req := httptest.NewRequest("GET", target, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				require.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+			for key, value := range tt.expectedHeader {
+				require.Equal(t, value, rr.Header().Get(key), key)
+			}
+		})
+	}
+}
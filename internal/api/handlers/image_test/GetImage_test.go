@@ -41,12 +41,14 @@ func TestGetImage(t *testing.T) {
 					CreatedAt:     time.Date(2025, 10, 21, 12, 0, 0, 0, time.UTC),
 					Processed:     true,
 				}
-				db.On("GetImage", mock.Anything, id).Return(img, nil).Once()
+				db.On("GetImage", mock.Anything, "default", id).Return(img, nil).Once()
+				is.On("GetURL", mock.Anything, "test/processed/test.png", time.Hour).
+					Return("http://minio:9000/images/test/processed/test.png?X-Amz-Signature=abc", nil).Once()
 			},
 			in:             InputData{id: "10"},
 			expectedStatus: http.StatusOK,
 			expectedData: map[string]string{
-				"result": "test/processed/test.png",
+				"url": "http://minio:9000/images/test/processed/test.png?X-Amz-Signature=abc",
 			},
 		},
 		{
@@ -59,7 +61,7 @@ func TestGetImage(t *testing.T) {
 					CreatedAt:     time.Date(2025, 10, 21, 12, 0, 0, 0, time.UTC),
 					Processed:     false,
 				}
-				db.On("GetImage", mock.Anything, id).Return(img, nil).Once()
+				db.On("GetImage", mock.Anything, "default", id).Return(img, nil).Once()
 			},
 			in:             InputData{id: "10"},
 			expectedStatus: http.StatusAccepted,
@@ -70,7 +72,7 @@ func TestGetImage(t *testing.T) {
 		{
 			name: "not found image",
 			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore, id int) {
-				db.On("GetImage", mock.Anything, id).Return(model.ImageInRepo{}, sql.ErrNoRows).Once()
+				db.On("GetImage", mock.Anything, "default", id).Return(model.ImageInRepo{}, sql.ErrNoRows).Once()
 			},
 			in:             InputData{id: "20"},
 			expectedStatus: http.StatusNotFound,
@@ -83,13 +85,14 @@ func TestGetImage(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDB := mocks.NewMockStorager(t)
+			mockImageStorage := mocks.NewMockImageStore(t)
 
 			id, err := strconv.Atoi(tt.in.id)
 			require.NoError(t, err)
 
-			tt.setupMock(mockDB, nil, id)
+			tt.setupMock(mockDB, mockImageStorage, id)
 
-			h := handlers.NewHandler(mockDB, nil, nil)
+			h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
 
 			rr := httptest.NewRecorder()
 			g, _ := gin.CreateTestContext(rr)
@@ -107,6 +110,12 @@ func TestGetImage(t *testing.T) {
 			require.NoError(t, err)
 
 			require.Contains(t, tt.expectedData["result"], response["result"])
+			if url, ok := tt.expectedData["url"]; ok {
+				require.Equal(t, url, response["url"])
+				expiresAt, err := time.Parse(time.RFC3339, response["expires_at"])
+				require.NoError(t, err)
+				require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+			}
 			mockDB.AssertExpectations(t)
 
 		})
@@ -0,0 +1,190 @@
+package imagetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func TestGetImagesPresignedURLs(t *testing.T) {
+	images := []model.ImageInRepo{
+		{ID: 2, UploadsPath: "uploads/2.png", ProcessedPath: "processed/2.png", Processed: true},
+		{ID: 1, UploadsPath: "uploads/1.png"},
+	}
+
+	mockDB := mocks.NewMockStorager(t)
+	mockImageStorage := mocks.NewMockImageStore(t)
+
+	mockDB.On("GetImages", mock.Anything, "default", mock.Anything).Return(images, nil).Once()
+	mockDB.On("GetCountImages", mock.Anything, "default", mock.Anything).Return(2, nil).Once()
+	mockImageStorage.On("GetManyURL", mock.Anything, []string{"processed/2.png"}, 30*time.Minute).
+		Return([]string{"http://cdn/processed/2.png?sig=1"}, nil).Once()
+
+	h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+	h.URLExpiry = 30 * time.Minute
+
+	rr := httptest.NewRecorder()
+	g, _ := gin.CreateTestContext(rr)
+	g.Request = httptest.NewRequest("GET", "/images", nil)
+
+	h.GetImages(g)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response struct {
+		Images []struct {
+			ID  int    `json:"id"`
+			URL string `json:"url"`
+		} `json:"images"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response.Images, 2)
+	require.Equal(t, "http://cdn/processed/2.png?sig=1", response.Images[0].URL)
+	require.Empty(t, response.Images[1].URL)
+	require.WithinDuration(t, time.Now().Add(30*time.Minute), response.ExpiresAt, time.Minute)
+}
+
+func TestGetImagesFilter(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected model.ImageFilter
+	}{
+		{
+			name:     "defaults",
+			query:    "",
+			expected: model.ImageFilter{Sort: model.SortCreatedAt, Desc: true, Limit: 21},
+		},
+		{
+			name:  "all parameters",
+			query: "sort=size&order=asc&limit=5&status=pending&format=jpg&owner=bob&from=2025-01-01&to=2025-02-01T12:00:00Z",
+			expected: model.ImageFilter{
+				Sort: model.SortSize, Limit: 6, Status: model.StatusPending, Owner: "bob",
+				Extensions: []string{".jpg", ".jpeg"}, From: from, To: to,
+			},
+		},
+		{
+			name:     "limit above the maximum",
+			query:    "sort=id&limit=1000",
+			expected: model.ImageFilter{Sort: model.SortID, Desc: true, Limit: 101},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockImageStorage := mocks.NewMockImageStore(t)
+
+			var got model.ImageFilter
+			mockDB.On("GetImages", mock.Anything, "default", mock.Anything).Run(func(args mock.Arguments) {
+				got = args.Get(2).(model.ImageFilter)
+			}).Return([]model.ImageInRepo{}, nil).Once()
+			mockDB.On("GetCountImages", mock.Anything, "default", mock.Anything).Return(0, nil).Once()
+			mockImageStorage.On("GetManyURL", mock.Anything, []string{}, mock.Anything).Return([]string{}, nil).Once()
+
+			h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+			rr := httptest.NewRecorder()
+			g, _ := gin.CreateTestContext(rr)
+			g.Request = httptest.NewRequest("GET", "/images?"+tt.query, nil)
+
+			h.GetImages(g)
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestGetImagesInvalidQuery(t *testing.T) {
+	for _, query := range []string{
+		"sort=name", "order=up", "limit=0", "limit=x", "status=done", "format=bmp",
+		"from=yesterday", "cursor=!!!", "cursor=e30",
+	} {
+		t.Run(query, func(t *testing.T) {
+			h := handlers.NewHandler(mocks.NewMockStorager(t), nil, mocks.NewMockImageStore(t), nil, "")
+			rr := httptest.NewRecorder()
+			g, _ := gin.CreateTestContext(rr)
+			g.Request = httptest.NewRequest("GET", "/images?"+query, nil)
+
+			h.GetImages(g)
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
+
+func TestGetImagesCursor(t *testing.T) {
+	created := time.Date(2025, 10, 21, 12, 0, 0, 0, time.UTC)
+	page := func(ids ...int) []model.ImageInRepo {
+		images := make([]model.ImageInRepo, len(ids))
+		for i, id := range ids {
+			images[i] = model.ImageInRepo{ID: id, UploadsPath: "uploads/x.png", CreatedAt: created}
+		}
+		return images
+	}
+
+	type response struct {
+		Images []struct {
+			ID int `json:"id"`
+		} `json:"images"`
+		NextCursor string `json:"next_cursor"`
+		PrevCursor string `json:"prev_cursor"`
+	}
+
+	mockDB := mocks.NewMockStorager(t)
+	mockImageStorage := mocks.NewMockImageStore(t)
+	h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+
+	get := func(query string, filter func(model.ImageFilter) bool, images []model.ImageInRepo) response {
+		mockDB.On("GetImages", mock.Anything, "default", mock.MatchedBy(filter)).Return(images, nil).Once()
+		mockDB.On("GetCountImages", mock.Anything, "default", mock.Anything).Return(5, nil).Once()
+		mockImageStorage.On("GetManyURL", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		g, _ := gin.CreateTestContext(rr)
+		g.Request = httptest.NewRequest("GET", "/images?order=asc&limit=2"+query, nil)
+		h.GetImages(g)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var res response
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		return res
+	}
+
+	first := get("", func(f model.ImageFilter) bool { return f.After == nil && !f.Desc && f.Limit == 3 }, page(1, 2, 3))
+	require.Len(t, first.Images, 2)
+	require.NotEmpty(t, first.NextCursor)
+	require.Empty(t, first.PrevCursor)
+
+	second := get("&cursor="+first.NextCursor, func(f model.ImageFilter) bool {
+		return f.After != nil && f.After.ID == 2 && f.After.CreatedAt.Equal(created) && !f.Desc
+	}, page(3, 4, 5))
+	require.Equal(t, 3, second.Images[0].ID)
+	require.NotEmpty(t, second.PrevCursor)
+
+	back := get("&cursor="+second.PrevCursor, func(f model.ImageFilter) bool {
+		return f.After != nil && f.After.ID == 3 && f.Desc
+	}, page(2, 1))
+	require.Equal(t, 1, back.Images[0].ID, "pages before the cursor keep the order")
+	require.Equal(t, 2, back.Images[1].ID)
+	require.NotEmpty(t, back.NextCursor)
+	require.Empty(t, back.PrevCursor)
+
+	rr := httptest.NewRecorder()
+	g, _ := gin.CreateTestContext(rr)
+	g.Request = httptest.NewRequest("GET", "/images?order=desc&cursor="+first.NextCursor, nil)
+	h.GetImages(g)
+	require.Equal(t, http.StatusBadRequest, rr.Code, "cursor of another order")
+}
@@ -0,0 +1,42 @@
+package imagetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+)
+
+func TestGetPresets(t *testing.T) {
+	h := handlers.NewHandler(nil, nil, nil, newTestPresets(t), "")
+
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	c.Request = httptest.NewRequest(http.MethodGet, "/presets", nil)
+
+	h.GetPresets(c)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Default string         `json:"default"`
+		Presets []model.Preset `json:"presets"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, "small", resp.Default)
+	require.Len(t, resp.Presets, 2)
+	require.Equal(t, "small", resp.Presets[0].Name)
+	require.Equal(t, model.Preset{
+		Name:    "large",
+		Width:   1024,
+		Height:  768,
+		Mode:    "fit",
+		Format:  "jpeg",
+		Quality: 80,
+	}, resp.Presets[1])
+}
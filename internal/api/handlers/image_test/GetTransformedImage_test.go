@@ -0,0 +1,175 @@
+package imagetest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+	"ImageProcessor/internal/service"
+)
+
+const testURLSecret = "secret"
+
+func testPNG(t *testing.T, w, h int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+	}
+	img.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buf, img))
+	return buf.Bytes()
+}
+
+func TestGetTransformedImage(t *testing.T) {
+	original := testPNG(t, 200, 100)
+	variant := "processed/t/7/w_50,m_fit"
+
+	tests := []struct {
+		name           string
+		signature      string
+		options        string
+		setupMock      func(*mocks.MockStorager, *mocks.MockImageStore)
+		expectedStatus int
+		expectedSize   image.Point
+	}{
+		{
+			name:           "bad signature",
+			signature:      "forged",
+			options:        "w_50",
+			setupMock:      func(db *mocks.MockStorager, is *mocks.MockImageStore) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "invalid options",
+			signature:      service.SignURL(testURLSecret, "", "w_50,m_zoom", 7),
+			options:        "w_50,m_zoom",
+			setupMock:      func(db *mocks.MockStorager, is *mocks.MockImageStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "cached variant",
+			signature: service.SignURL(testURLSecret, "", "w_50", 7),
+			options:   "w_50",
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore) {
+				db.On("GetImage", mock.Anything, "default", 7).Return(model.ImageInRepo{ID: 7, UploadsPath: "uploads/7.png", Processed: true}, nil).Once()
+				is.On("Exists", mock.Anything, variant).Return(true, nil).Once()
+				is.On("Open", mock.Anything, variant).Return(seekCloser{bytes.NewReader(testPNG(t, 50, 25))}, model.ObjectInfo{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedSize:   image.Pt(50, 25),
+		},
+		{
+			name:      "build variant",
+			signature: service.SignURL(testURLSecret, "", "m_fit,w_50", 7),
+			options:   "m_fit,w_50",
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore) {
+				db.On("GetImage", mock.Anything, "default", 7).Return(model.ImageInRepo{ID: 7, UploadsPath: "uploads/7.png", Processed: true}, nil).Once()
+				is.On("Exists", mock.Anything, variant).Return(false, nil).Once()
+				is.On("Download", mock.Anything, "uploads/7.png").Return(io.NopCloser(bytes.NewReader(original)), nil).Once()
+
+				var stored []byte
+				is.On("Upload", mock.Anything, mock.Anything, variant, mock.Anything).Run(func(args mock.Arguments) {
+					data, err := io.ReadAll(args.Get(1).(io.Reader))
+					require.NoError(t, err)
+					stored = data
+				}).Return(nil).Once()
+				is.EXPECT().Open(mock.Anything, variant).RunAndReturn(func(context.Context, string) (io.ReadSeekCloser, model.ObjectInfo, error) {
+					return seekCloser{bytes.NewReader(stored)}, model.ObjectInfo{}, nil
+				}).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedSize:   image.Pt(50, 25),
+		},
+		{
+			name:      "redacted image",
+			signature: service.SignURL(testURLSecret, "", "w_50", 7),
+			options:   "w_50",
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore) {
+				db.On("GetImage", mock.Anything, "default", 7).Return(model.ImageInRepo{
+					ID:            7,
+					UploadsPath:   "uploads/7.png",
+					ProcessedPath: "processed/7.png",
+					Processed:     true,
+					Result:        &model.ProcessingResult{Redactions: []model.Redaction{{Width: 10, Height: 10, Method: "fill"}}},
+				}, nil).Once()
+				is.On("Exists", mock.Anything, variant).Return(false, nil).Once()
+				is.On("Download", mock.Anything, "processed/7.png").Return(io.NopCloser(bytes.NewReader(original)), nil).Once()
+
+				var stored []byte
+				is.On("Upload", mock.Anything, mock.Anything, variant, mock.Anything).Run(func(args mock.Arguments) {
+					data, err := io.ReadAll(args.Get(1).(io.Reader))
+					require.NoError(t, err)
+					stored = data
+				}).Return(nil).Once()
+				is.EXPECT().Open(mock.Anything, variant).RunAndReturn(func(context.Context, string) (io.ReadSeekCloser, model.ObjectInfo, error) {
+					return seekCloser{bytes.NewReader(stored)}, model.ObjectInfo{}, nil
+				}).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedSize:   image.Pt(50, 25),
+		},
+		{
+			name:      "not processed image",
+			signature: service.SignURL(testURLSecret, "", "w_50", 7),
+			options:   "w_50",
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore) {
+				db.On("GetImage", mock.Anything, "default", 7).Return(model.ImageInRepo{ID: 7, UploadsPath: "uploads/7.png"}, nil).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:      "deleted image with a cached variant",
+			signature: service.SignURL(testURLSecret, "", "w_50", 7),
+			options:   "w_50",
+			setupMock: func(db *mocks.MockStorager, is *mocks.MockImageStore) {
+				db.On("GetImage", mock.Anything, "default", 7).Return(model.ImageInRepo{}, nil).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockImageStorage := mocks.NewMockImageStore(t)
+			tt.setupMock(mockDB, mockImageStorage)
+
+			h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, testURLSecret)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request = httptest.NewRequest("GET", fmt.Sprintf("/t/%s/%s/7", tt.signature, tt.options), nil)
+			c.Params = gin.Params{
+				gin.Param{Key: "signature", Value: tt.signature},
+				gin.Param{Key: "options", Value: tt.options},
+				gin.Param{Key: "id", Value: "7"},
+			}
+
+			h.GetTransformedImage(c)
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			require.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+			cfg, err := png.DecodeConfig(rr.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSize, image.Pt(cfg.Width, cfg.Height))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package imagetest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func readUpload(args mock.Arguments) {
+	_, _ = io.ReadAll(args.Get(1).(io.Reader))
+}
+
+func TestUploadQuota(t *testing.T) {
+	data, err := os.ReadFile(testImagePath)
+	require.NoError(t, err)
+	size := int64(len(data))
+
+	t.Run("within quota", func(t *testing.T) {
+		quota := model.Quota{MaxBytes: 10 * size, MaxImages: 5}
+
+		mockDB := mocks.NewMockStorager(t)
+		mockProducer := mocks.NewMockImageTaskProducer(t)
+		mockImageStorage := mocks.NewMockImageStore(t)
+
+		mockDB.On("GetUsage", mock.Anything, "default", "alice").Return(model.Usage{Bytes: size, Images: 1}, nil).Once()
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), int64(-1)).Run(readUpload).Return(nil).Once()
+		mockDB.On("CreateImage", mock.Anything, mock.MatchedBy(func(img model.ImageInCreate) bool {
+			return img.Size == size && img.Quota == quota
+		})).Return(1, nil).Once()
+		mockProducer.On("Publish", mock.Anything, mock.Anything).Return(nil).Once()
+
+		h := handlers.NewHandler(mockDB, mockProducer, mockImageStorage, newTestPresets(t), "")
+		h.Quota = quota
+
+		req := createMultipartRequest(t, testImagePath, Parameters{typeProcessing: "thumbnail"})
+		rr := httptest.NewRecorder()
+		h.UploadImage(newIdentityContext(rr, req, alice, ""))
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "5", rr.Header().Get("X-Quota-Images-Limit"))
+		require.Equal(t, "1", rr.Header().Get("X-Quota-Images-Used"))
+		require.NotEmpty(t, rr.Header().Get("X-Quota-Bytes-Limit"))
+	})
+
+	t.Run("image count reached", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockDB.On("GetUsage", mock.Anything, "default", "alice").Return(model.Usage{Images: 2}, nil).Once()
+
+		h := handlers.NewHandler(mockDB, nil, mocks.NewMockImageStore(t), newTestPresets(t), "")
+		h.Quota = model.Quota{MaxImages: 2}
+
+		req := createMultipartRequest(t, testImagePath, Parameters{typeProcessing: "thumbnail"})
+		rr := httptest.NewRecorder()
+		h.UploadImage(newIdentityContext(rr, req, alice, ""))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		require.Equal(t, "2", rr.Header().Get("X-Quota-Images-Used"))
+	})
+
+	t.Run("file larger than the remaining bytes", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockImageStorage := mocks.NewMockImageStore(t)
+
+		mockDB.On("GetUsage", mock.Anything, "default", "").Return(model.Usage{Bytes: 100}, nil).Once()
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), int64(-1)).Run(readUpload).Return(nil).Once()
+		mockImageStorage.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+
+		h := handlers.NewHandler(mockDB, nil, mockImageStorage, newTestPresets(t), "")
+		h.Quota = model.Quota{MaxBytes: 100 + size/2}
+
+		req := createMultipartRequest(t, testImagePath, Parameters{typeProcessing: "thumbnail"})
+		rr := httptest.NewRecorder()
+		h.UploadImage(newIdentityContext(rr, req, nil, ""))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		require.Equal(t, "100", rr.Header().Get("X-Quota-Bytes-Used"))
+	})
+
+	t.Run("url import with the bytes used up", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockDB.On("GetUsage", mock.Anything, "default", "alice").Return(model.Usage{Bytes: 100}, nil).Once()
+
+		h := handlers.NewHandler(mockDB, nil, mocks.NewMockImageStore(t), newTestPresets(t), "")
+		h.Quota = model.Quota{MaxBytes: 100}
+
+		fields := url.Values{"url": {"https://example.com/cat"}, "type_processing": {"thumbnail"}}
+		req := httptest.NewRequest("POST", "/upload/url", strings.NewReader(fields.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		h.UploadFromURL(newIdentityContext(rr, req, alice, ""))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("quota used up concurrently", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockImageStorage := mocks.NewMockImageStore(t)
+
+		mockDB.On("GetUsage", mock.Anything, "default", "alice").Return(model.Usage{}, nil).Once()
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), int64(-1)).Run(readUpload).Return(nil).Once()
+		mockDB.On("CreateImage", mock.Anything, mock.Anything).Return(0, repository.ErrQuotaExceeded).Once()
+		mockImageStorage.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+
+		h := handlers.NewHandler(mockDB, nil, mockImageStorage, newTestPresets(t), "")
+		h.Quota = model.Quota{MaxImages: 1}
+
+		req := createMultipartRequest(t, testImagePath, Parameters{typeProcessing: "thumbnail"})
+		rr := httptest.NewRecorder()
+		h.UploadImage(newIdentityContext(rr, req, alice, ""))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("batch beyond the remaining bytes", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockProducer := mocks.NewMockImageTaskProducer(t)
+		mockImageStorage := mocks.NewMockImageStore(t)
+
+		mockDB.On("GetUsage", mock.Anything, "default", "alice").Return(model.Usage{}, nil).Once()
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), int64(-1)).Run(readUpload).Return(nil).Twice()
+		mockImageStorage.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+		mockDB.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+		mockProducer.On("Publish", mock.Anything, mock.Anything).Return(nil).Once()
+
+		h := handlers.NewHandler(mockDB, mockProducer, mockImageStorage, newTestPresets(t), "")
+		h.Quota = model.Quota{MaxBytes: size + size/2}
+
+		req := createBatchRequest(t, []string{testImagePath, testImagePath}, map[string]string{"type_processing": "thumbnail"})
+		rr := httptest.NewRecorder()
+		h.UploadBatch(newIdentityContext(rr, req, alice, ""))
+		require.Equal(t, http.StatusMultiStatus, rr.Code)
+		require.Contains(t, rr.Body.String(), "storage quota exceeded")
+	})
+}
@@ -0,0 +1,113 @@
+package imagetest
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func createProcessRequest(t *testing.T, fields map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/image/5/process", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestReprocessImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		fields         map[string]string
+		setupMock      func(*mocks.MockStorager, *mocks.MockImageTaskProducer)
+		expectedStatus int
+	}{
+		{
+			name: "reprocess with resize",
+			fields: map[string]string{
+				"type_processing": "resize",
+				"width":           "300",
+				"height":          "200",
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer) {
+				db.On("GetImage", mock.Anything, "default", 5).Return(model.ImageInRepo{ID: 5, UploadsPath: "uploads/5.png"}, nil).Once()
+				db.On("CreateDerivative", mock.Anything, "default", model.Derivative{ImageID: 5, TypeProcessing: "resize"}).Return(11, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return task.ImageID == 5 && task.DerivativeID == 11 && task.UploadsPath == "uploads/5.png" &&
+						task.TypeProcessing == "resize" && *task.Parameters.Width == 300
+				})).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "publish failure removes the derivative",
+			fields: map[string]string{
+				"type_processing": "resize",
+				"width":           "300",
+				"height":          "200",
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer) {
+				db.On("GetImage", mock.Anything, "default", 5).Return(model.ImageInRepo{ID: 5, UploadsPath: "uploads/5.png"}, nil).Once()
+				db.On("CreateDerivative", mock.Anything, "default", model.Derivative{ImageID: 5, TypeProcessing: "resize"}).Return(11, nil).Once()
+				prod.On("Publish", mock.Anything, mock.Anything).Return(fmt.Errorf("kafka down")).Once()
+				db.On("DeleteDerivative", mock.Anything, "default", 11).Return(nil).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "invalid parameters",
+			fields: map[string]string{
+				"type_processing": "resize",
+				"width":           "wide",
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer) {
+				db.On("GetImage", mock.Anything, "default", 5).Return(model.ImageInRepo{ID: 5, UploadsPath: "uploads/5.png"}, nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "image not found",
+			fields: map[string]string{
+				"type_processing": "thumbnail",
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer) {
+				db.On("GetImage", mock.Anything, "default", 5).Return(model.ImageInRepo{}, nil).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockProducer := mocks.NewMockImageTaskProducer(t)
+			tt.setupMock(mockDB, mockProducer)
+
+			h := handlers.NewHandler(mockDB, mockProducer, nil, newTestPresets(t), "")
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request = createProcessRequest(t, tt.fields)
+			c.Params = gin.Params{
+				gin.Param{Key: "id", Value: "5"},
+			}
+
+			h.ReprocessImage(c)
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
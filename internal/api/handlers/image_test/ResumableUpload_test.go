@@ -0,0 +1,237 @@
+package imagetest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/api/resumable"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func newUploadRouter(t *testing.T, h *handlers.Handler, maxSize int64) *gin.Engine {
+	store, err := resumable.NewStore(t.TempDir(), maxSize, time.Hour)
+	require.NoError(t, err)
+	h.Uploads = store
+	return newUploadRoutes(h, nil)
+}
+
+// newUploadRoutes serves the upload routes of h to identity, nil is an
+// anonymous client.
+func newUploadRoutes(h *handlers.Handler, identity *auth.Identity) *gin.Engine {
+	r := gin.New()
+	if identity != nil {
+		r.Use(func(c *gin.Context) {
+			auth.SetIdentity(c, *identity)
+		})
+	}
+	r.POST("/upload/resumable", h.CreateUpload)
+	r.HEAD("/upload/resumable/:id", h.GetUploadOffset)
+	r.GET("/upload/resumable/:id", h.GetUploadOffset)
+	r.PATCH("/upload/resumable/:id", h.PatchUpload)
+	r.POST("/upload/resumable/:id/complete", h.CompleteUpload)
+	r.DELETE("/upload/resumable/:id", h.DeleteUpload)
+	return r
+}
+
+func createUpload(t *testing.T, r *gin.Engine, filename string, length int) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/upload/resumable", nil)
+	req.Header.Set("Upload-Length", strconv.Itoa(length))
+	req.Header.Set("Upload-Metadata", "filename "+base64.StdEncoding.EncodeToString([]byte(filename)))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func patchUpload(r *gin.Engine, location string, offset int, chunk []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("PATCH", location, bytes.NewReader(chunk))
+	req.Header.Set("Content-Type", "application/offset+octet-stream")
+	req.Header.Set("Upload-Offset", strconv.Itoa(offset))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestResumableUpload(t *testing.T) {
+	data, err := os.ReadFile(testImagePath)
+	require.NoError(t, err)
+	half := len(data) / 2
+
+	mockDB := mocks.NewMockStorager(t)
+	mockProducer := mocks.NewMockImageTaskProducer(t)
+	mockStore := mocks.NewMockImageStore(t)
+
+	h := handlers.NewHandler(mockDB, mockProducer, mockStore, newTestPresets(t), "")
+	r := newUploadRouter(t, h, 1<<20)
+
+	rr := createUpload(t, r, "photo.jpg", len(data))
+	require.Equal(t, http.StatusCreated, rr.Code)
+	location := rr.Header().Get("Location")
+	require.True(t, strings.HasPrefix(location, "/upload/resumable/"))
+
+	rr = patchUpload(r, location, 0, data[:half])
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	require.Equal(t, strconv.Itoa(half), rr.Header().Get("Upload-Offset"))
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("HEAD", location, nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, strconv.Itoa(half), rr.Header().Get("Upload-Offset"))
+	require.Equal(t, strconv.Itoa(len(data)), rr.Header().Get("Upload-Length"))
+
+	rr = patchUpload(r, location, 0, data[:half])
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, strconv.Itoa(half), rr.Header().Get("Upload-Offset"))
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("POST", location+"/complete", nil))
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = patchUpload(r, location, half, data[half:])
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	require.Equal(t, strconv.Itoa(len(data)), rr.Header().Get("Upload-Offset"))
+
+	var uploaded []byte
+	mockStore.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(func(name string) bool {
+		return strings.HasPrefix(name, "uploads/") && strings.HasSuffix(name, ".jpg")
+	}), int64(len(data))).Run(func(args mock.Arguments) {
+		uploaded, _ = io.ReadAll(args.Get(1).(io.Reader))
+	}).Return(nil).Once()
+	mockDB.On("CreateImage", mock.Anything, mock.Anything).Return(7, nil).Once()
+	mockProducer.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+		return task.ImageID == 7 && task.TypeProcessing == "thumbnail" && *task.Parameters.Width == 150
+	})).Return(nil).Once()
+
+	form := url.Values{"type_processing": {"thumbnail"}}
+	req := httptest.NewRequest("POST", location+"/complete", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, data, uploaded)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("HEAD", location, nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestResumableUploadErrors(t *testing.T) {
+	h := handlers.NewHandler(mocks.NewMockStorager(t), nil, mocks.NewMockImageStore(t), nil, "")
+	r := newUploadRouter(t, h, 100)
+
+	t.Run("too large", func(t *testing.T) {
+		rr := createUpload(t, r, "photo.jpg", 101)
+		require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		rr := createUpload(t, r, "notes.txt", 10)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("chunk beyond length", func(t *testing.T) {
+		rr := createUpload(t, r, "photo.png", 4)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		rr = patchUpload(r, rr.Header().Get("Location"), 0, []byte("123456"))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("wrong content type", func(t *testing.T) {
+		rr := createUpload(t, r, "photo.png", 4)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		req := httptest.NewRequest("PATCH", rr.Header().Get("Location"), strings.NewReader("1234"))
+		req.Header.Set("Upload-Offset", "0")
+		rr = httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		rr := createUpload(t, r, "photo.png", 4)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		location := rr.Header().Get("Location")
+
+		rr = httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("DELETE", location, nil))
+		require.Equal(t, http.StatusNoContent, rr.Code)
+
+		rr = patchUpload(r, location, 0, []byte("1234"))
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", "/upload/resumable/../../etc", nil))
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("session of another user", func(t *testing.T) {
+		aliceRoutes, bobRoutes := newUploadRoutes(h, alice), newUploadRoutes(h, bob)
+
+		rr := createUpload(t, aliceRoutes, "photo.png", 4)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		location := rr.Header().Get("Location")
+
+		rr = patchUpload(bobRoutes, location, 0, []byte("1234"))
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		rr = patchUpload(r, location, 0, []byte("1234"))
+		require.Equal(t, http.StatusNotFound, rr.Code)
+
+		for _, req := range []*http.Request{
+			httptest.NewRequest("HEAD", location, nil),
+			httptest.NewRequest("POST", location+"/complete", nil),
+			httptest.NewRequest("DELETE", location, nil),
+		} {
+			rr = httptest.NewRecorder()
+			bobRoutes.ServeHTTP(rr, req)
+			require.Equal(t, http.StatusNotFound, rr.Code, req.Method)
+		}
+
+		rr = patchUpload(aliceRoutes, location, 0, []byte("1234"))
+		require.Equal(t, http.StatusNoContent, rr.Code)
+	})
+}
+
+func TestResumableUploadQuota(t *testing.T) {
+	mockDB := mocks.NewMockStorager(t)
+	mockDB.On("GetUsage", mock.Anything, "default", "alice").Return(model.Usage{Bytes: 98}, nil).Once()
+
+	h := handlers.NewHandler(mockDB, nil, mocks.NewMockImageStore(t), newTestPresets(t), "")
+	h.Quota = model.Quota{MaxBytes: 100}
+	newUploadRouter(t, h, 100)
+	r := newUploadRoutes(h, alice)
+
+	rr := createUpload(t, r, "photo.png", 4)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	location := rr.Header().Get("Location")
+
+	rr = patchUpload(r, location, 0, []byte("1234"))
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("POST", location+"/complete", nil))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("HEAD", location, nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+}
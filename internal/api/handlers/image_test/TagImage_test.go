@@ -0,0 +1,63 @@
+package imagetest
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func TestTagImage(t *testing.T) {
+	owned := model.ImageInRepo{ID: 1, Owner: "alice", Visibility: model.VisibilityPublic}
+
+	tests := []struct {
+		name           string
+		identity       *auth.Identity
+		tag            string
+		expectTag      bool
+		expectedStatus int
+	}{
+		{name: "own image", identity: alice, tag: "Summer", expectTag: true, expectedStatus: http.StatusOK},
+		{name: "image of another user", identity: bob, tag: "summer", expectedStatus: http.StatusForbidden},
+		{name: "invalid tag", identity: alice, tag: "a b", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			if tt.expectedStatus != http.StatusBadRequest {
+				mockDB.On("GetImage", mock.Anything, "default", 1).Return(owned, nil).Once()
+			}
+			if tt.expectTag {
+				mockDB.On("TagImage", mock.Anything, "default", 1, "summer").Return(nil).Once()
+			}
+
+			h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+			params := gin.Params{{Key: "id", Value: "1"}, {Key: "tag", Value: tt.tag}}
+			rr := httptest.NewRecorder()
+			h.TagImage(newParamsContext(rr, httptest.NewRequest("PUT", "/image/1/tags/x", nil), tt.identity, params))
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+
+	t.Run("untag missing tag", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockDB.On("GetImage", mock.Anything, "default", 1).Return(owned, nil).Once()
+		mockDB.On("UntagImage", mock.Anything, "default", 1, "summer").Return(sql.ErrNoRows).Once()
+
+		h := handlers.NewHandler(mockDB, nil, nil, nil, "")
+		params := gin.Params{{Key: "id", Value: "1"}, {Key: "tag", Value: "summer"}}
+		rr := httptest.NewRecorder()
+		h.UntagImage(newParamsContext(rr, httptest.NewRequest("DELETE", "/image/1/tags/summer", nil), alice, params))
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
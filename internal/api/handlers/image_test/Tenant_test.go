@@ -0,0 +1,84 @@
+package imagetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+	"ImageProcessor/internal/service"
+)
+
+var brandUser = &auth.Identity{UserID: "carol", Tenant: "brand", Scopes: []string{auth.ScopeUpload, auth.ScopeRead}}
+
+func newTestTenants(t *testing.T) *service.Tenants {
+	tenants, err := service.NewTenants([]model.TenantConfig{
+		{ID: "brand", Formats: []string{service.FormatJPEG}, DefaultPreset: "large"},
+	}, newTestPresets(t))
+	require.NoError(t, err)
+	return tenants
+}
+
+func TestTenantUpload(t *testing.T) {
+	t.Run("objects and rows of the tenant", func(t *testing.T) {
+		mockDB := mocks.NewMockStorager(t)
+		mockProducer := mocks.NewMockImageTaskProducer(t)
+		mockImageStorage := mocks.NewMockImageStore(t)
+
+		inTenant := func(name string) bool { return strings.HasPrefix(name, "tenants/brand/uploads/") }
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(inTenant), int64(-1)).Return(nil).Once()
+		mockDB.On("CreateImage", mock.Anything, mock.MatchedBy(func(img model.ImageInCreate) bool {
+			return img.Tenant == "brand" && img.Owner == "carol"
+		})).Return(1, nil).Once()
+		mockProducer.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+			return task.Tenant == "brand" && *task.Parameters.Preset == "large"
+		})).Return(nil).Once()
+
+		h := handlers.NewHandler(mockDB, mockProducer, mockImageStorage, newTestPresets(t), "")
+		h.Tenants = newTestTenants(t)
+
+		req := createMultipartRequest(t, testImagePath, Parameters{typeProcessing: "thumbnail"})
+		rr := httptest.NewRecorder()
+		h.UploadImage(newIdentityContext(rr, req, brandUser, ""))
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("format not allowed", func(t *testing.T) {
+		h := handlers.NewHandler(mocks.NewMockStorager(t), nil, mocks.NewMockImageStore(t), newTestPresets(t), "")
+		h.Tenants = newTestTenants(t)
+
+		req := createMultipartRequest(t, testWatermarkPath, Parameters{typeProcessing: "thumbnail"})
+		rr := httptest.NewRecorder()
+		h.UploadImage(newIdentityContext(rr, req, brandUser, ""))
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), "not allowed")
+	})
+}
+
+func TestTenantScopedLookups(t *testing.T) {
+	mockDB := mocks.NewMockStorager(t)
+	mockDB.On("GetImage", mock.Anything, "brand", 4).Return(model.ImageInRepo{}, nil).Once()
+	ofCarol := mock.MatchedBy(func(f model.ImageFilter) bool { return f.Access == model.ImageAccess{Owner: "carol"} })
+	mockDB.On("GetImages", mock.Anything, "brand", ofCarol).Return([]model.ImageInRepo{}, nil).Once()
+	mockDB.On("GetCountImages", mock.Anything, "brand", ofCarol).Return(0, nil).Once()
+
+	mockImageStorage := mocks.NewMockImageStore(t)
+	mockImageStorage.On("GetManyURL", mock.Anything, []string{}, mock.Anything).Return([]string{}, nil).Once()
+
+	h := handlers.NewHandler(mockDB, nil, mockImageStorage, nil, "")
+
+	rr := httptest.NewRecorder()
+	h.GetImage(newIdentityContext(rr, httptest.NewRequest("GET", "/image/4", nil), brandUser, "4"))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.GetImages(newIdentityContext(rr, httptest.NewRequest("GET", "/images", nil), brandUser, ""))
+	require.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,196 @@
+package imagetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func createBatchRequest(t *testing.T, files []string, fields map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for key, value := range fields {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+
+	for _, path := range files {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+
+		part, err := writer.CreateFormFile("img", filepath.Base(path))
+		require.NoError(t, err)
+		_, err = io.Copy(part, file)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/upload/batch", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadBatch(t *testing.T) {
+	tests := []struct {
+		name             string
+		files            []string
+		fields           map[string]string
+		setupMock        func(*mocks.MockStorager, *mocks.MockImageTaskProducer, *mocks.MockImageStore)
+		expectedStatus   int
+		expectedUploaded int
+		expectedErrors   []bool
+	}{
+		{
+			name:   "shared spec",
+			files:  []string{testImagePath, testImagePath},
+			fields: map[string]string{"type_processing": "resize", "width": "100", "height": "50"},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(2)
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(2, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return task.TypeProcessing == "resize" && *task.Parameters.Width == 100
+				})).Return(nil).Times(2)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedUploaded: 2,
+			expectedErrors:   []bool{false, false},
+		},
+		{
+			name:   "partial failure",
+			files:  []string{testImagePath, testInvalidDataFormatPath, testImagePath},
+			fields: map[string]string{"type_processing": "thumbnail"},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(2)
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Times(2)
+				prod.On("Publish", mock.Anything, mock.Anything).Return(nil).Times(2)
+			},
+			expectedStatus:   http.StatusMultiStatus,
+			expectedUploaded: 2,
+			expectedErrors:   []bool{false, true, false},
+		},
+		{
+			name:  "per-file specs",
+			files: []string{testImagePath, testImagePath, testImagePath},
+			fields: map[string]string{"specs": `[
+				{"type_processing": "rotate", "parameters": {"angle": 90}},
+				{"type_processing": "blur"},
+				{"type_processing": "thumbnail", "preset": "large"}
+			]`},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+				is.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Times(2)
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return task.TypeProcessing == "rotate" && *task.Parameters.Angle == 90
+				})).Return(nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return task.TypeProcessing == "thumbnail" && *task.Parameters.Width == 1024
+				})).Return(nil).Once()
+			},
+			expectedStatus:   http.StatusMultiStatus,
+			expectedUploaded: 2,
+			expectedErrors:   []bool{false, true, false},
+		},
+		{
+			name:   "publish failure",
+			files:  []string{testImagePath},
+			fields: map[string]string{"type_processing": "thumbnail"},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.Anything).Return(fmt.Errorf("kafka down")).Once()
+				db.On("DeleteImage", mock.Anything, "default", 1).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus:   http.StatusMultiStatus,
+			expectedUploaded: 0,
+			expectedErrors:   []bool{true},
+		},
+		{
+			name:   "specs count mismatch",
+			files:  []string{testImagePath, testImagePath},
+			fields: map[string]string{"specs": `[{"type_processing": "thumbnail"}]`},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(2)
+				is.On("Delete", mock.Anything, mock.Anything).Return(nil).Times(2)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "shared watermark",
+			files:  []string{testImagePath},
+			fields: map[string]string{"type_processing": "watermark"},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid shared spec",
+			files:  []string{testImagePath},
+			fields: map[string]string{"type_processing": "resize", "width": "wide"},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockProducer := mocks.NewMockImageTaskProducer(t)
+			mockImageStorage := mocks.NewMockImageStore(t)
+			tt.setupMock(mockDB, mockProducer, mockImageStorage)
+
+			h := handlers.NewHandler(mockDB, mockProducer, mockImageStorage, newTestPresets(t), "")
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request = createBatchRequest(t, tt.files, tt.fields)
+
+			h.UploadBatch(c)
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedErrors == nil {
+				return
+			}
+
+			var resp struct {
+				Uploaded int `json:"uploaded"`
+				Results  []struct {
+					File       string `json:"file"`
+					ImageID    int    `json:"image_id"`
+					ObjectName string `json:"object_name"`
+					Error      string `json:"error"`
+				} `json:"results"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tt.expectedUploaded, resp.Uploaded)
+			require.Len(t, resp.Results, len(tt.files))
+			for i, failed := range tt.expectedErrors {
+				require.Equal(t, filepath.Base(tt.files[i]), resp.Results[i].File)
+				require.Equal(t, failed, resp.Results[i].Error != "", resp.Results[i].Error)
+				require.Equal(t, failed, resp.Results[i].ImageID == 0)
+				require.Equal(t, failed, resp.Results[i].ObjectName == "")
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package imagetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+)
+
+func TestUploadFromURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		fields         url.Values
+		setupMock      func(*mocks.MockStorager, *mocks.MockImageTaskProducer)
+		expectedStatus int
+	}{
+		{
+			name: "queue fetch",
+			fields: url.Values{
+				"url":             {"https://example.com/photos/cat.jpg?size=big"},
+				"type_processing": {"thumbnail"},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer) {
+				db.On("CreateImage", mock.Anything, mock.MatchedBy(func(img model.ImageInCreate) bool {
+					return strings.HasPrefix(img.UploadsPath, "uploads/") && strings.HasSuffix(img.UploadsPath, ".jpg")
+				})).Return(3, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return task.ImageID == 3 && task.SourceURL == "https://example.com/photos/cat.jpg?size=big" &&
+						task.TypeProcessing == "thumbnail"
+				})).Return(nil).Once()
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name: "unsupported scheme",
+			fields: url.Values{
+				"url":             {"file:///etc/passwd"},
+				"type_processing": {"thumbnail"},
+			},
+			setupMock:      func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "missing url",
+			fields: url.Values{
+				"type_processing": {"thumbnail"},
+			},
+			setupMock:      func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid parameters",
+			fields: url.Values{
+				"url":             {"https://example.com/cat.jpg"},
+				"type_processing": {"resize"},
+				"width":           {"wide"},
+			},
+			setupMock:      func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewMockStorager(t)
+			mockProducer := mocks.NewMockImageTaskProducer(t)
+			tt.setupMock(mockDB, mockProducer)
+
+			h := handlers.NewHandler(mockDB, mockProducer, mocks.NewMockImageStore(t), newTestPresets(t), "")
+
+			rr := httptest.NewRecorder()
+			g, _ := gin.CreateTestContext(rr)
+			g.Request = httptest.NewRequest("POST", "/upload/url", strings.NewReader(tt.fields.Encode()))
+			g.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			h.UploadFromURL(g)
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
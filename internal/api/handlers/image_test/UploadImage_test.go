@@ -2,12 +2,15 @@ package imagetest
 
 import (
 	"bytes"
+	"database/sql"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -15,7 +18,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"ImageProcessor/internal/api/handlers"
+	"ImageProcessor/internal/model"
 	"ImageProcessor/internal/repository/mocks"
+	"ImageProcessor/internal/service"
 )
 
 const (
@@ -24,12 +29,27 @@ const (
 	testInvalidDataFormatPath = "testdata/invalid.txt"
 )
 
+func newTestPresets(t *testing.T) *service.Presets {
+	presets, err := service.NewPresets([]model.Preset{
+		{Name: "small", Width: 150, Height: 150, Mode: service.ResizeFill},
+		{Name: "large", Width: 1024, Height: 768, Mode: service.ResizeFit, Format: service.FormatJPEG, Quality: 80},
+	}, "small")
+	require.NoError(t, err)
+	return presets
+}
+
+func isUpload(name string) bool {
+	return strings.HasPrefix(name, "uploads/")
+}
+
 type Parameters struct {
 	typeProcessing string
 	inputFilePath  string
 	height         string
 	width          string
 	watermarkPath  string
+	interpolation  string
+	fields         map[string]string
 }
 
 func createMultipartRequest(t *testing.T, filePath string, param Parameters) *http.Request {
@@ -42,8 +62,16 @@ func createMultipartRequest(t *testing.T, filePath string, param Parameters) *ht
 
 	err = writer.WriteField("type_processing", param.typeProcessing)
 	require.NoError(t, err)
+	if param.interpolation != "" {
+		err = writer.WriteField("interpolation", param.interpolation)
+		require.NoError(t, err)
+	}
+	for key, value := range param.fields {
+		err = writer.WriteField(key, value)
+		require.NoError(t, err)
+	}
 	switch param.typeProcessing {
-	case "resize":
+	case "resize", "crop":
 		err = writer.WriteField("height", param.height)
 		require.NoError(t, err)
 		err = writer.WriteField("width", param.width)
@@ -110,6 +138,423 @@ func TestUploadImage(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name: "resize with interpolation",
+			param: Parameters{
+				typeProcessing: "resize",
+				inputFilePath:  testImagePath,
+				height:         "200",
+				width:          "200",
+				interpolation:  "nearest",
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return task.Parameters.Interpolation != nil && *task.Parameters.Interpolation == "nearest"
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "unsupported interpolation",
+			param: Parameters{
+				typeProcessing: "resize",
+				inputFilePath:  testImagePath,
+				height:         "200",
+				width:          "200",
+				interpolation:  "lanczos",
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rotate processing",
+			param: Parameters{
+				typeProcessing: "rotate",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"angle":      "45",
+					"flip":       "horizontal",
+					"background": "#00000000",
+					"expand":     "true",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return *task.Parameters.Angle == 45 && *task.Parameters.Flip == "horizontal" && *task.Parameters.Expand
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "rotate without angle and flip",
+			param: Parameters{
+				typeProcessing: "rotate",
+				inputFilePath:  testImagePath,
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rotate with invalid background",
+			param: Parameters{
+				typeProcessing: "rotate",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"angle":      "90",
+					"background": "blue",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "adjust processing",
+			param: Parameters{
+				typeProcessing: "adjust",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"sepia":      "true",
+					"brightness": "0.1",
+					"gamma":      "1.2",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					p := task.Parameters
+					return *p.Sepia && *p.Brightness == 0.1 && *p.Gamma == 1.2 && p.Contrast == nil
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "adjust with out of range contrast",
+			param: Parameters{
+				typeProcessing: "adjust",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"contrast": "3",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "filter processing",
+			param: Parameters{
+				typeProcessing: "filter",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"filter": "custom",
+					"kernel": "0, -1, 0, -1, 5, -1, 0, -1, 0",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return *task.Parameters.Filter == "custom" && len(task.Parameters.Kernel) == 9
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "filter with unknown name",
+			param: Parameters{
+				typeProcessing: "filter",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"filter": "median",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "resize with sharpen",
+			param: Parameters{
+				typeProcessing: "resize",
+				inputFilePath:  testImagePath,
+				height:         "100",
+				width:          "100",
+				fields: map[string]string{
+					"sharpen": "0.8",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return *task.Parameters.Sharpen == 0.8
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "redact processing",
+			param: Parameters{
+				typeProcessing: "redact",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"redactions": `[{"x":10,"y":10,"width":50,"height":20,"method":"pixelate","block_size":8},{"x":0,"y":0,"width":5,"height":5,"method":"fill","color":"#000"}]`,
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					r := task.Parameters.Redactions
+					return len(r) == 2 && r[0].BlockSize == 8 && r[1].Method == "fill"
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "redact with unknown method",
+			param: Parameters{
+				typeProcessing: "redact",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"redactions": `[{"x":10,"y":10,"width":50,"height":20,"method":"erase"}]`,
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "trim processing",
+			param: Parameters{
+				typeProcessing: "trim",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"trim_color": "#fff",
+					"tolerance":  "12",
+					"padding":    "4",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					p := task.Parameters
+					return *p.TrimColor == "#fff" && *p.Tolerance == 12 && *p.Padding == 4
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "trim with negative padding",
+			param: Parameters{
+				typeProcessing: "trim",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"padding": "-1",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "round processing",
+			param: Parameters{
+				typeProcessing: "round",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"corner_radius": "16",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return *task.Parameters.CornerRadius == 16
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "border without width",
+			param: Parameters{
+				typeProcessing: "border",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"border_color": "#333",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "thumbnail with default preset",
+			param: Parameters{
+				typeProcessing: "thumbnail",
+				inputFilePath:  testImagePath,
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return *task.Parameters.Preset == "small" && *task.Parameters.Width == 150 &&
+						*task.Parameters.ResizeMode == service.ResizeFill && task.Parameters.Format == nil
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "thumbnail with preset",
+			param: Parameters{
+				typeProcessing: "thumbnail",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"preset": "large",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return *task.Parameters.Width == 1024 && *task.Parameters.Height == 768 &&
+						*task.Parameters.ResizeMode == service.ResizeFit &&
+						*task.Parameters.Format == service.FormatJPEG && *task.Parameters.Quality == 80
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "thumbnail with unknown preset",
+			param: Parameters{
+				typeProcessing: "thumbnail",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"preset": "huge",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "upload with transformation preset",
+			param: Parameters{
+				inputFilePath: testImagePath,
+				fields: map[string]string{
+					"preset": "product-card",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				width, height, sharpen := 400, 300, 0.5
+				db.On("GetTransformation", mock.Anything, "default", "product-card").Return(model.Transformation{
+					Name: "product-card",
+					Steps: []model.Step{
+						{TypeProcessing: "thumbnail", Parameters: model.ProcessingParams{Width: &width, Height: &height, Sharpen: &sharpen}},
+						{TypeProcessing: "watermark"},
+					},
+					Format:        "jpeg",
+					Quality:       80,
+					WatermarkPath: "transformations/logo.png",
+				}, nil).Once()
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return task.TypeProcessing == service.TypePipeline && len(task.Steps) == 2 &&
+						*task.Steps[1].Parameters.WatermarkPath == "transformations/logo.png" &&
+						*task.Parameters.Format == "jpeg" && *task.Parameters.Quality == 80
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "upload with unknown transformation preset",
+			param: Parameters{
+				inputFilePath: testImagePath,
+				fields: map[string]string{
+					"preset": "missing",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("GetTransformation", mock.Anything, "default", "missing").Return(model.Transformation{}, sql.ErrNoRows).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "upload without type and preset",
+			param: Parameters{
+				inputFilePath: testImagePath,
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "crop processing",
+			param: Parameters{
+				typeProcessing: "crop",
+				inputFilePath:  testImagePath,
+				height:         "100",
+				width:          "200",
+				fields: map[string]string{
+					"gravity": "smart",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+				prod.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+					return *task.Parameters.Gravity == "smart" && *task.Parameters.Width == 200 && *task.Parameters.Height == 100
+				})).Return(nil).Once()
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "thumbnail with unknown gravity",
+			param: Parameters{
+				typeProcessing: "thumbnail",
+				inputFilePath:  testImagePath,
+				fields: map[string]string{
+					"gravity": "top",
+				},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "watermark processing",
 			param: Parameters{
@@ -125,6 +570,48 @@ func TestUploadImage(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name: "resize larger than the size limit",
+			param: Parameters{
+				typeProcessing: "resize",
+				inputFilePath:  testImagePath,
+				height:         "100000",
+				width:          "100000",
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rotate with non-finite angle",
+			param: Parameters{
+				typeProcessing: "rotate",
+				inputFilePath:  testImagePath,
+				fields:         map[string]string{"angle": "NaN", "expand": "true"},
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), mock.Anything).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "publish fails",
+			param: Parameters{
+				typeProcessing: "thumbnail",
+				inputFilePath:  testImagePath,
+			},
+			setupMock: func(db *mocks.MockStorager, prod *mocks.MockImageTaskProducer, is *mocks.MockImageStore) {
+				is.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), mock.Anything).Return(nil).Once()
+				db.On("CreateImage", mock.Anything, mock.Anything).Return(7, nil).Once()
+				prod.On("Publish", mock.Anything, mock.Anything).Return(fmt.Errorf("broker down")).Once()
+				db.On("DeleteImage", mock.Anything, "default", 7).Return(nil).Once()
+				is.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
 		{
 			name: "invalid file format",
 			param: Parameters{
@@ -143,7 +630,7 @@ func TestUploadImage(t *testing.T) {
 			mockProducer := mocks.NewMockImageTaskProducer(t)
 			mockImageStorage := mocks.NewMockImageStore(t)
 			tt.setupMock(mockDB, mockProducer, mockImageStorage)
-			h := handlers.NewHandler(mockDB, mockProducer, mockImageStorage)
+			h := handlers.NewHandler(mockDB, mockProducer, mockImageStorage, newTestPresets(t), "")
 			rr := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(rr)
 			c.Request = createMultipartRequest(t, tt.param.inputFilePath, tt.param)
@@ -157,3 +644,147 @@ func TestUploadImage(t *testing.T) {
 		})
 	}
 }
+
+func TestUploadImageStreaming(t *testing.T) {
+	data, err := os.ReadFile(testImagePath)
+	require.NoError(t, err)
+
+	t.Run("fields after the file", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("img", "photo.jpg")
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteField("type_processing", "resize"))
+		require.NoError(t, writer.WriteField("width", "300"))
+		require.NoError(t, writer.WriteField("height", "200"))
+		require.NoError(t, writer.Close())
+
+		mockDB := mocks.NewMockStorager(t)
+		mockProducer := mocks.NewMockImageTaskProducer(t)
+		mockImageStorage := mocks.NewMockImageStore(t)
+
+		var uploaded []byte
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), int64(-1)).Run(func(args mock.Arguments) {
+			uploaded, _ = io.ReadAll(args.Get(1).(io.Reader))
+		}).Return(nil).Once()
+		mockDB.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+		mockProducer.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+			return task.TypeProcessing == "resize" && *task.Parameters.Width == 300 && *task.Parameters.Height == 200
+		})).Return(nil).Once()
+
+		h := handlers.NewHandler(mockDB, mockProducer, mockImageStorage, newTestPresets(t), "")
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		c.Request = httptest.NewRequest("POST", "/upload", body)
+		c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		h.UploadImage(c)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, data, uploaded)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, writer.WriteField("type_processing", "thumbnail"))
+		require.NoError(t, writer.Close())
+
+		h := handlers.NewHandler(mocks.NewMockStorager(t), nil, mocks.NewMockImageStore(t), newTestPresets(t), "")
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		c.Request = httptest.NewRequest("POST", "/upload", body)
+		c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		h.UploadImage(c)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("unused watermark is removed", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, writer.WriteField("type_processing", "thumbnail"))
+		part, err := writer.CreateFormFile("watermark", "logo.png")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("logo"))
+		require.NoError(t, err)
+		part, err = writer.CreateFormFile("img", "photo.jpg")
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		mockDB := mocks.NewMockStorager(t)
+		mockProducer := mocks.NewMockImageTaskProducer(t)
+		mockImageStorage := mocks.NewMockImageStore(t)
+
+		isWatermark := func(name string) bool { return strings.HasPrefix(name, "watermarks/") }
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isWatermark), int64(-1)).Return(nil).Once()
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), int64(-1)).Return(nil).Once()
+		mockImageStorage.On("Delete", mock.Anything, mock.MatchedBy(isWatermark)).Return(nil).Once()
+		mockDB.On("CreateImage", mock.Anything, mock.Anything).Return(1, nil).Once()
+		mockProducer.On("Publish", mock.Anything, mock.MatchedBy(func(task model.ImageTask) bool {
+			return task.Parameters.WatermarkPath == nil
+		})).Return(nil).Once()
+
+		h := handlers.NewHandler(mockDB, mockProducer, mockImageStorage, newTestPresets(t), "")
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		c.Request = httptest.NewRequest("POST", "/upload", body)
+		c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		h.UploadImage(c)
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	watermarkRequest := func(t *testing.T, filename string, size int) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, writer.WriteField("type_processing", "watermark"))
+		part, err := writer.CreateFormFile("img", "photo.jpg")
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+		part, err = writer.CreateFormFile("watermark", filename)
+		require.NoError(t, err)
+		_, err = part.Write(bytes.Repeat([]byte{0}, size))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest("POST", "/upload", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	t.Run("watermark of unsupported format", func(t *testing.T) {
+		mockImageStorage := mocks.NewMockImageStore(t)
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), int64(-1)).Run(readUpload).Return(nil).Once()
+		mockImageStorage.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+
+		h := handlers.NewHandler(mocks.NewMockStorager(t), nil, mockImageStorage, newTestPresets(t), "")
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		c.Request = watermarkRequest(t, "logo.svg", 4)
+
+		h.UploadImage(c)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("watermark larger than the limit", func(t *testing.T) {
+		isWatermark := func(name string) bool { return strings.HasPrefix(name, "watermarks/") }
+		mockImageStorage := mocks.NewMockImageStore(t)
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isUpload), int64(-1)).Run(readUpload).Return(nil).Once()
+		mockImageStorage.On("Upload", mock.Anything, mock.Anything, mock.MatchedBy(isWatermark), int64(-1)).Run(readUpload).Return(nil).Once()
+		mockImageStorage.On("Delete", mock.Anything, mock.MatchedBy(isWatermark)).Return(nil).Once()
+		mockImageStorage.On("Delete", mock.Anything, mock.MatchedBy(isUpload)).Return(nil).Once()
+
+		h := handlers.NewHandler(mocks.NewMockStorager(t), nil, mockImageStorage, newTestPresets(t), "")
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		c.Request = watermarkRequest(t, "logo.png", 16<<20+1)
+
+		h.UploadImage(c)
+		require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/service"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// formatExtensions are the upload extensions of the formats GET /images
+// filters by.
+var formatExtensions = map[string][]string{
+	service.FormatJPEG: {".jpg", ".jpeg"},
+	service.FormatPNG:  {".png"},
+	service.FormatGIF:  {".gif"},
+}
+
+// listCursor is the content of the opaque cursor of GET /images. It keeps
+// the order it was created for, so a cursor cannot be reused with another
+// sort. Back cursors return the page before the image.
+type listCursor struct {
+	Sort string `json:"s"`
+	Desc bool   `json:"d,omitempty"`
+	Back bool   `json:"b,omitempty"`
+	model.ImageCursor
+}
+
+func encodeCursor(filter model.ImageFilter, img model.ImageInRepo, back bool) string {
+	cursor := listCursor{Sort: filter.Sort, Desc: filter.Desc, Back: back, ImageCursor: model.ImageCursor{ID: img.ID}}
+	switch filter.Sort {
+	case model.SortCreatedAt:
+		cursor.CreatedAt = img.CreatedAt
+	case model.SortSize:
+		cursor.Size = img.Size
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string, filter model.ImageFilter) (listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return listCursor{}, fmt.Errorf("invalid cursor")
+	}
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return listCursor{}, fmt.Errorf("invalid cursor")
+	}
+	if cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+		return listCursor{}, fmt.Errorf("cursor does not match sort and order")
+	}
+	return cursor, nil
+}
+
+// listFilter reads the query parameters of GET /images and reports whether
+// the cursor goes back. Listings are sorted by created_at descending unless
+// sort and order say otherwise.
+func listFilter(c *ginext.Context) (model.ImageFilter, bool, error) {
+	filter := model.ImageFilter{
+		Access: imageAccess(c),
+		Owner:  c.Query("owner"),
+		Sort:   c.DefaultQuery("sort", model.SortCreatedAt),
+		Limit:  defaultPageSize,
+	}
+
+	if !slices.Contains([]string{model.SortCreatedAt, model.SortID, model.SortSize}, filter.Sort) {
+		return model.ImageFilter{}, false, fmt.Errorf("unsupported sort %q", filter.Sort)
+	}
+	switch order := c.DefaultQuery("order", "desc"); order {
+	case "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return model.ImageFilter{}, false, fmt.Errorf("unsupported order %q", order)
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return model.ImageFilter{}, false, fmt.Errorf("limit must be a positive number")
+		}
+		filter.Limit = min(limit, maxPageSize)
+	}
+
+	if tag := c.Query("tag"); tag != "" {
+		var err error
+		filter.Tag, err = service.NormalizeTag(tag)
+		if err != nil {
+			return model.ImageFilter{}, false, err
+		}
+	}
+	if album := c.Query("album"); album != "" {
+		var err error
+		filter.AlbumID, err = strconv.Atoi(album)
+		if err != nil || filter.AlbumID < 1 {
+			return model.ImageFilter{}, false, fmt.Errorf("invalid album")
+		}
+	}
+
+	filter.Status = c.Query("status")
+	if filter.Status != "" && filter.Status != model.StatusProcessed && filter.Status != model.StatusPending {
+		return model.ImageFilter{}, false, fmt.Errorf("unsupported status %q", filter.Status)
+	}
+
+	if format := c.Query("format"); format != "" {
+		if format == "jpg" {
+			format = service.FormatJPEG
+		}
+		exts, ok := formatExtensions[format]
+		if !ok {
+			return model.ImageFilter{}, false, fmt.Errorf("unsupported format %q", format)
+		}
+		filter.Extensions = exts
+	}
+
+	var err error
+	filter.From, err = timeQuery(c, "from")
+	if err != nil {
+		return model.ImageFilter{}, false, err
+	}
+	filter.To, err = timeQuery(c, "to")
+	if err != nil {
+		return model.ImageFilter{}, false, err
+	}
+
+	if str := c.Query("cursor"); str != "" {
+		cursor, err := decodeCursor(str, filter)
+		if err != nil {
+			return model.ImageFilter{}, false, err
+		}
+		filter.After = &cursor.ImageCursor
+		return filter, cursor.Back, nil
+	}
+	return filter, false, nil
+}
+
+// timeQuery parses an optional RFC 3339 time or a date.
+func timeQuery(c *ginext.Context, name string) (time.Time, error) {
+	str := c.Query(name)
+	if str == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, str); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, str)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC 3339 time or a date", name)
+	}
+	return t, nil
+}
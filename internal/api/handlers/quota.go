@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+)
+
+var errStorageQuota = fmt.Errorf("storage quota exceeded")
+
+// clientUserID returns the user of the client, anonymous clients have none.
+func clientUserID(c *ginext.Context) string {
+	identity, ok := auth.FromContext(c)
+	if !ok {
+		return ""
+	}
+	return identity.UserID
+}
+
+// checkQuota sets the quota headers and returns how many bytes the next
+// upload of the client may have, -1 when there is no byte limit. It fails
+// with 413 when no upload fits anymore.
+func (h *Handler) checkQuota(c *ginext.Context) (int64, int, error) {
+	if h.Quota == (model.Quota{}) {
+		return -1, http.StatusOK, nil
+	}
+
+	usage, err := h.DB.GetUsage(c.Request.Context(), auth.Tenant(c), clientUserID(c))
+	if err != nil {
+		return 0, http.StatusInternalServerError, err
+	}
+	h.setQuotaHeaders(c, usage)
+
+	if h.Quota.MaxImages > 0 && usage.Images >= h.Quota.MaxImages {
+		return 0, http.StatusRequestEntityTooLarge, fmt.Errorf("image quota exceeded")
+	}
+	if h.Quota.MaxBytes == 0 {
+		return -1, http.StatusOK, nil
+	}
+	remaining := h.Quota.MaxBytes - usage.Bytes
+	if remaining <= 0 {
+		return 0, http.StatusRequestEntityTooLarge, errStorageQuota
+	}
+	return remaining, http.StatusOK, nil
+}
+
+func (h *Handler) setQuotaHeaders(c *ginext.Context, usage model.Usage) {
+	if h.Quota.MaxBytes > 0 {
+		c.Header("X-Quota-Bytes-Limit", strconv.FormatInt(h.Quota.MaxBytes, 10))
+		c.Header("X-Quota-Bytes-Used", strconv.FormatInt(usage.Bytes, 10))
+	}
+	if h.Quota.MaxImages > 0 {
+		c.Header("X-Quota-Images-Limit", strconv.Itoa(h.Quota.MaxImages))
+		c.Header("X-Quota-Images-Used", strconv.Itoa(usage.Images))
+	}
+}
+
+// createStatus maps an error of createAndPublish to the response status.
+func createStatus(err error) int {
+	if errors.Is(err, repository.ErrQuotaExceeded) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
+
+// countingReader counts the bytes read and stops with errStorageQuota after
+// more than limit bytes, a negative limit disables the check.
+type countingReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	if cr.exceeded() {
+		return n, errStorageQuota
+	}
+	return n, err
+}
+
+func (cr *countingReader) exceeded() bool {
+	return cr.limit >= 0 && cr.n > cr.limit
+}
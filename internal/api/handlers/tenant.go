@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/service"
+)
+
+// checkUploadFormat accepts image files in the formats the tenant of the
+// request may upload.
+func (h *Handler) checkUploadFormat(c *ginext.Context, filename string) error {
+	ext := filepath.Ext(filename)
+	if !slices.Contains(imageExtensions, ext) {
+		return fmt.Errorf("unsupported format")
+	}
+
+	format := strings.TrimPrefix(ext, ".")
+	if format == "jpg" {
+		format = service.FormatJPEG
+	}
+	if h.Tenants != nil && !h.Tenants.AllowsFormat(auth.Tenant(c), format) {
+		return fmt.Errorf("format %s is not allowed", format)
+	}
+	return nil
+}
+
+// preset returns the named preset, an empty name selects the default preset
+// of the tenant.
+func (h *Handler) preset(c *ginext.Context, name string) (model.Preset, error) {
+	if name == "" && h.Tenants != nil {
+		name = h.Tenants.Get(auth.Tenant(c)).DefaultPreset
+	}
+	return h.Presets.Get(name)
+}
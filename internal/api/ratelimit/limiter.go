@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"ImageProcessor/internal/api/auth"
+)
+
+// Limiter is a token bucket per client: a bucket holds up to burst tokens
+// and refills rate tokens per second, every request takes one token.
+type Limiter struct {
+	rate  float64
+	burst int
+	// Now returns the current time, tests replace it.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// sweepInterval is how often buckets that are full again are dropped, so
+// idle clients do not stay in memory.
+const sweepInterval = time.Minute
+
+// New returns a limiter of rate requests per second with bursts of burst
+// requests. rate must be positive, burst is raised to 1 when smaller.
+func New(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{rate: rate, burst: burst, Now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+// Allow takes a token from the bucket of key. It returns the tokens left
+// and, when the request is not allowed, the time until the next token.
+func (l *Limiter) Allow(key string) (bool, int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, 0, wait
+	}
+	b.tokens--
+	return true, int(b.tokens), 0
+}
+
+// Len returns the number of clients with a bucket.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	full := time.Duration(float64(l.burst) / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Middleware limits the requests per authenticated user, anonymous requests
+// are limited per client IP. It runs after the auth middleware, so requests
+// with invalid credentials never get a bucket. A nil limiter allows
+// everything.
+func (l *Limiter) Middleware() ginext.HandlerFunc {
+	return func(c *ginext.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+
+		ok, remaining, wait := l.Allow(clientKey(c))
+		c.Header("X-RateLimit-Limit", strconv.Itoa(l.burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, ginext.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// clientKey identifies the client of the request. Users are only unique
+// within their tenant.
+func clientKey(c *ginext.Context) string {
+	identity, ok := auth.FromContext(c)
+	if !ok {
+		return "ip:" + c.ClientIP()
+	}
+	return "user:" + auth.Tenant(c) + "/" + identity.UserID
+}
@@ -0,0 +1,109 @@
+package ratelimittest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/api/auth"
+	"ImageProcessor/internal/api/ratelimit"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := ratelimit.New(2, 3)
+	l.Now = func() time.Time { return now }
+
+	for i := 2; i >= 0; i-- {
+		ok, remaining, _ := l.Allow("a")
+		require.True(t, ok)
+		require.Equal(t, i, remaining)
+	}
+
+	ok, _, wait := l.Allow("a")
+	require.False(t, ok)
+	require.Equal(t, 500*time.Millisecond, wait)
+
+	ok, _, _ = l.Allow("b")
+	require.True(t, ok, "buckets are per key")
+
+	now = now.Add(500 * time.Millisecond)
+	ok, remaining, _ := l.Allow("a")
+	require.True(t, ok)
+	require.Equal(t, 0, remaining)
+
+	now = now.Add(time.Hour)
+	ok, remaining, _ = l.Allow("a")
+	require.True(t, ok)
+	require.Equal(t, 2, remaining, "bucket is capped at burst")
+}
+
+func TestMiddleware(t *testing.T) {
+	l := ratelimit.New(1, 1)
+	l.Now = func() time.Time { return time.Unix(0, 0) }
+
+	tokens, err := auth.NewStaticTokens("key-a=alice, key-b=alice", nil)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.GET("/", auth.Middleware(false, tokens), l.Middleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(token, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = ip + ":1234"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	require.Equal(t, http.StatusOK, do("", "10.0.0.1").Code)
+	rr := do("", "10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	require.Equal(t, "1", rr.Header().Get("Retry-After"))
+	require.Equal(t, "0", rr.Header().Get("X-RateLimit-Remaining"))
+
+	require.Equal(t, http.StatusOK, do("", "10.0.0.2").Code, "other IP")
+	require.Equal(t, http.StatusOK, do("key-a", "10.0.0.1").Code, "user is limited apart from the IP")
+	require.Equal(t, http.StatusTooManyRequests, do("key-a", "10.0.0.2").Code, "same user from another IP")
+	require.Equal(t, http.StatusTooManyRequests, do("key-b", "10.0.0.3").Code, "same user with another token")
+
+	for i := range 10 {
+		require.Equal(t, http.StatusUnauthorized, do(fmt.Sprintf("junk-%d", i), "10.0.0.4").Code)
+	}
+	require.Equal(t, 3, l.Len(), "invalid tokens get no bucket")
+}
+
+func TestLimiterEvictsIdleClients(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := ratelimit.New(1, 5)
+	l.Now = func() time.Time { return now }
+
+	for i := range 100 {
+		l.Allow(fmt.Sprintf("client-%d", i))
+	}
+	require.Equal(t, 100, l.Len())
+
+	now = now.Add(2 * time.Minute)
+	l.Allow("client-0")
+	require.Equal(t, 1, l.Len())
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *ratelimit.Limiter
+	r := gin.New()
+	r.GET("/", l.Middleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for range 3 {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+}
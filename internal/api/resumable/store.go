@@ -0,0 +1,255 @@
+package resumable
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wb-go/wbf/zlog"
+)
+
+var (
+	ErrNotFound       = fmt.Errorf("upload session not found")
+	ErrOffsetMismatch = fmt.Errorf("upload offset mismatch")
+	ErrTooLarge       = fmt.Errorf("upload exceeds declared length")
+	ErrIncomplete     = fmt.Errorf("upload is not complete")
+)
+
+// Session is an upload in progress. Tenant and Owner are the client that
+// created it, anonymous clients have no owner.
+type Session struct {
+	ID        string    `json:"id"`
+	Tenant    string    `json:"tenant"`
+	Owner     string    `json:"owner,omitempty"`
+	Filename  string    `json:"filename"`
+	Length    int64     `json:"length"`
+	Offset    int64     `json:"offset"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s Session) Complete() bool {
+	return s.Offset == s.Length
+}
+
+// Store keeps upload sessions on local disk: <id>.json holds the session and
+// <id>.part the bytes received so far, its size is the current offset.
+// Sessions older than ttl are removed when new ones are created.
+type Store struct {
+	dir     string
+	maxSize int64
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func NewStore(dir string, maxSize int64, ttl time.Duration) (*Store, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, maxSize: maxSize, ttl: ttl, locks: make(map[string]*sync.Mutex)}, nil
+}
+
+func (s *Store) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *Store) Create(tenant, owner, filename string, length int64) (Session, error) {
+	if length <= 0 || length > s.maxSize {
+		return Session{}, ErrTooLarge
+	}
+
+	s.cleanup()
+
+	session := Session{
+		ID:        uuid.New().String(),
+		Tenant:    tenant,
+		Owner:     owner,
+		Filename:  filename,
+		Length:    length,
+		CreatedAt: time.Now(),
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return Session{}, err
+	}
+
+	err = os.WriteFile(s.infoPath(session.ID), data, 0o600)
+	if err != nil {
+		return Session{}, err
+	}
+
+	file, err := os.OpenFile(s.partPath(session.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return Session{}, err
+	}
+	return session, file.Close()
+}
+
+func (s *Store) Get(id string) (Session, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return Session{}, ErrNotFound
+	}
+
+	data, err := os.ReadFile(s.infoPath(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Session{}, ErrNotFound
+		}
+		return Session{}, err
+	}
+
+	var session Session
+	err = json.Unmarshal(data, &session)
+	if err != nil {
+		return Session{}, err
+	}
+
+	info, err := os.Stat(s.partPath(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Session{}, ErrNotFound
+		}
+		return Session{}, err
+	}
+	session.Offset = info.Size()
+	return session, nil
+}
+
+// Append writes r at offset, which must be the current offset. Bytes read
+// before r fails are kept so the client can resume from the new offset.
+func (s *Store) Append(id string, offset int64, r io.Reader) (Session, error) {
+	// Only existing sessions get a lock, so unknown ids do not grow the map.
+	if _, err := s.Get(id); err != nil {
+		return Session{}, err
+	}
+	lock := s.lock(id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := s.Get(id)
+	if err != nil {
+		return Session{}, err
+	}
+	if offset != session.Offset {
+		return session, ErrOffsetMismatch
+	}
+
+	file, err := os.OpenFile(s.partPath(id), os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return Session{}, err
+	}
+
+	remaining := session.Length - session.Offset
+	n, copyErr := io.Copy(file, io.LimitReader(r, remaining))
+	session.Offset += n
+
+	if err := file.Close(); err != nil {
+		return session, err
+	}
+	if copyErr != nil {
+		return session, copyErr
+	}
+
+	var extra [1]byte
+	if k, _ := r.Read(extra[:]); k > 0 {
+		return session, ErrTooLarge
+	}
+	return session, nil
+}
+
+// Open returns the data of a complete session.
+func (s *Store) Open(id string) (io.ReadCloser, Session, error) {
+	session, err := s.Get(id)
+	if err != nil {
+		return nil, Session{}, err
+	}
+	if !session.Complete() {
+		return nil, session, ErrIncomplete
+	}
+
+	file, err := os.Open(s.partPath(id))
+	if err != nil {
+		return nil, Session{}, err
+	}
+	return file, session, nil
+}
+
+func (s *Store) Delete(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrNotFound
+	}
+
+	err := os.Remove(s.infoPath(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	err = os.Remove(s.partPath(id))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.locks, id)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Store) cleanup() {
+	if s.ttl <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		zlog.Logger.Error().Msg(err.Error())
+		return
+	}
+
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < s.ttl {
+			continue
+		}
+
+		id := entry.Name()[:len(entry.Name())-len(".json")]
+		if err := s.Delete(id); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}
+}
+
+func (s *Store) lock(id string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, ok := s.locks[id]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[id] = lock
+	}
+	return lock
+}
+
+func (s *Store) infoPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *Store) partPath(id string) string {
+	return filepath.Join(s.dir, id+".part")
+}
@@ -23,6 +23,7 @@ type App struct {
 	Consumer     repository.ImageTaskConsumer
 	Producer     repository.ImageTaskProducer
 	ImageStorage repository.ImageStore
+	Fetcher      *service.Fetcher
 }
 
 func (a *App) Run(ctx context.Context) error {
@@ -61,13 +62,30 @@ func (a *App) Run(ctx context.Context) error {
 			ImageStorage: a.ImageStorage,
 		}
 		defer wg.Done()
-		var img model.ImageTask
 		for msg := range imgTaskCh {
+			var img model.ImageTask
 			err := json.Unmarshal(msg.Value, &img)
 			if err != nil {
 				zlog.Logger.Error().Msgf("Unmarshal image error: %s", err.Error())
 				continue
 			}
+			if img.Tenant == "" {
+				img.Tenant = model.DefaultTenant
+			}
+
+			if img.SourceURL != "" {
+				size, err := a.Fetcher.Fetch(ctx, a.ImageStorage, img.Tenant, img.SourceURL, img.UploadsPath)
+				if err != nil {
+					zlog.Logger.Error().Msgf("Fetch image error: %s", err.Error())
+					continue
+				}
+				err = a.DB.SetImageSize(ctx, img.Tenant, img.ImageID, size)
+				if err != nil {
+					zlog.Logger.Error().Msgf("Set image size error: %s", err.Error())
+					continue
+				}
+			}
+
 			is.Img = img
 			updateImg, err := service.ProcessImage(is)
 			if err != nil {
@@ -75,7 +93,17 @@ func (a *App) Run(ctx context.Context) error {
 				continue
 			}
 
-			err = a.DB.UpdateImage(ctx, updateImg)
+			if img.DerivativeID != 0 {
+				err = a.DB.UpdateDerivative(ctx, img.Tenant, model.Derivative{
+					ID:            img.DerivativeID,
+					ImageID:       img.ImageID,
+					ProcessedPath: updateImg.ProcessedPath,
+					Processed:     updateImg.Processed,
+					Result:        updateImg.Result,
+				})
+			} else {
+				err = a.DB.UpdateImage(ctx, img.Tenant, updateImg)
+			}
 			if err != nil {
 				zlog.Logger.Error().Msgf("Commit message error: %s", err.Error())
 				continue
@@ -1,26 +1,79 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	configwbf "github.com/wb-go/wbf/config"
+
+	"ImageProcessor/internal/model"
 )
 
 type Config struct {
-	Server  ServerConfig
-	Kafka   KafkaConfig
-	Postgre PostgreConfig
-	Minio   MinioConfig
+	Server     ServerConfig
+	Kafka      KafkaConfig
+	Postgre    PostgreConfig
+	Minio      MinioConfig
+	Thumbnails ThumbnailConfig
+	Uploads    UploadConfig
+	Auth       AuthConfig
+	Tenants    []model.TenantConfig
+	RateLimit  RateLimitConfig
+	Quota      model.Quota
+}
+
+// RateLimitConfig limits the requests per second of every client with
+// bursts of Burst requests, RPS 0 disables the limit.
+type RateLimitConfig struct {
+	RPS   float64
+	Burst int
+}
+
+// AuthConfig.Tokens holds comma separated "token=user_id" pairs, users in
+// Admins may access every image.
+type AuthConfig struct {
+	Required bool
+	Tokens   string
+	Admins   []string
+	JWT      JWTConfig
+}
+
+// JWTConfig enables JWT authentication when JWKSFile or Secret is set.
+type JWTConfig struct {
+	JWKSFile    string
+	Secret      string
+	Issuer      string
+	Audience    string
+	UserClaim   string
+	ScopesClaim string
+	TenantClaim string
+	Leeway      time.Duration
+	// AllowNoExpiry accepts tokens without the exp claim.
+	AllowNoExpiry bool
 }
 
+func (j JWTConfig) Enabled() bool {
+	return j.JWKSFile != "" || j.Secret != ""
+}
+
+// MinioConfig.URLExpiry is the lifetime of presigned URLs in API responses.
 type MinioConfig struct {
-	Endpoint   string
-	User       string
-	Password   string
-	Sslmode    bool
-	BucketName string
+	Endpoint       string
+	PublicEndpoint string
+	User           string
+	Password       string
+	Sslmode        bool
+	BucketName     string
+	PublicRead     bool
+	URLExpiry      time.Duration
 }
 
 type ServerConfig struct {
-	Port string
+	Port            string
+	TransformSecret string
 }
 
 type KafkaConfig struct {
@@ -29,6 +82,22 @@ type KafkaConfig struct {
 	GroupID string
 }
 
+type ThumbnailConfig struct {
+	Default string
+	Presets []model.Preset
+}
+
+// UploadConfig configures resumable uploads: partial data is kept in Dir
+// until the upload is completed or older than SessionTTL.
+// FetchMaxSize and FetchTimeout limit images imported from URLs.
+type UploadConfig struct {
+	Dir          string
+	MaxSize      int64
+	SessionTTL   time.Duration
+	FetchMaxSize int64
+	FetchTimeout time.Duration
+}
+
 type PostgreConfig struct {
 	User     string
 	Password string
@@ -43,9 +112,18 @@ func NewConfig(file string) (*Config, error) {
 		return nil, err
 	}
 
+	urlExpiry := c.GetDuration("MINIO_URL_EXPIRY")
+	if urlExpiry <= 0 {
+		urlExpiry = defaultURLExpiry
+	}
+	if urlExpiry > maxURLExpiry {
+		return nil, fmt.Errorf("MINIO_URL_EXPIRY must not exceed %s", maxURLExpiry)
+	}
+
 	return &Config{
 		Server: ServerConfig{
-			Port: c.GetString("PORT"),
+			Port:            c.GetString("PORT"),
+			TransformSecret: c.GetString("TRANSFORM_URL_SECRET"),
 		},
 		Postgre: PostgreConfig{
 			Host:     c.GetString("POSTGRES_HOST"),
@@ -64,6 +142,165 @@ func NewConfig(file string) (*Config, error) {
 			Sslmode:    c.GetBool("MINIO_USE_SSL"),
 			BucketName: c.GetString("MINIO_BUCKET_NAME"),
 			Endpoint:   c.GetString("MINIO_ENDPOINT"),
+
+			PublicEndpoint: c.GetString("MINIO_PUBLIC_ENDPOINT"),
+			PublicRead:     c.GetBool("MINIO_PUBLIC_READ"),
+			URLExpiry:      urlExpiry,
+		},
+		Thumbnails: loadThumbnails(c),
+		Uploads:    loadUploads(c),
+		Auth: AuthConfig{
+			Required: c.GetBool("AUTH_REQUIRED"),
+			Tokens:   c.GetString("AUTH_TOKENS"),
+			Admins:   splitList(c.GetString("AUTH_ADMINS")),
+			JWT:      loadJWT(c),
+		},
+		Tenants: loadTenants(c),
+		RateLimit: RateLimitConfig{
+			RPS:   c.GetFloat64("RATE_LIMIT_RPS"),
+			Burst: c.GetInt("RATE_LIMIT_BURST"),
+		},
+		Quota: model.Quota{
+			MaxBytes:  int64(c.GetInt("QUOTA_MAX_BYTES")),
+			MaxImages: c.GetInt("QUOTA_MAX_IMAGES"),
 		},
 	}, nil
 }
+
+const (
+	defaultURLExpiry = time.Hour
+	maxURLExpiry     = 7 * 24 * time.Hour
+)
+
+const (
+	defaultUploadMaxSize    = 100 << 20
+	defaultUploadSessionTTL = 24 * time.Hour
+	defaultFetchMaxSize     = 20 << 20
+	defaultFetchTimeout     = 30 * time.Second
+)
+
+func loadJWT(c *configwbf.Config) JWTConfig {
+	j := JWTConfig{
+		JWKSFile:    c.GetString("JWT_JWKS_FILE"),
+		Secret:      c.GetString("JWT_SECRET"),
+		Issuer:      c.GetString("JWT_ISSUER"),
+		Audience:    c.GetString("JWT_AUDIENCE"),
+		UserClaim:   c.GetString("JWT_USER_CLAIM"),
+		ScopesClaim: c.GetString("JWT_SCOPES_CLAIM"),
+		TenantClaim: c.GetString("JWT_TENANT_CLAIM"),
+		Leeway:      c.GetDuration("JWT_LEEWAY"),
+
+		AllowNoExpiry: c.GetBool("JWT_ALLOW_NO_EXPIRY"),
+	}
+	if j.UserClaim == "" {
+		j.UserClaim = "sub"
+	}
+	if j.ScopesClaim == "" {
+		j.ScopesClaim = "scope"
+	}
+	if j.TenantClaim == "" {
+		j.TenantClaim = "tenant"
+	}
+	return j
+}
+
+// loadTenants reads the tenants listed in TENANTS, the settings of a tenant
+// are TENANT_<ID>_FORMATS and TENANT_<ID>_DEFAULT_PRESET.
+func loadTenants(c *configwbf.Config) []model.TenantConfig {
+	var tenants []model.TenantConfig
+	for _, id := range splitList(c.GetString("TENANTS")) {
+		key := func(field string) string {
+			return fmt.Sprintf("TENANT_%s_%s", strings.ToUpper(strings.ReplaceAll(id, "-", "_")), field)
+		}
+
+		tenants = append(tenants, model.TenantConfig{
+			ID:            id,
+			Formats:       splitList(c.GetString(key("FORMATS"))),
+			DefaultPreset: c.GetString(key("DEFAULT_PRESET")),
+		})
+	}
+	return tenants
+}
+
+func loadUploads(c *configwbf.Config) UploadConfig {
+	cfg := UploadConfig{
+		Dir:        c.GetString("UPLOAD_SESSIONS_DIR"),
+		MaxSize:    int64(c.GetInt("UPLOAD_MAX_SIZE")),
+		SessionTTL: c.GetDuration("UPLOAD_SESSION_TTL"),
+
+		FetchMaxSize: int64(c.GetInt("FETCH_MAX_SIZE")),
+		FetchTimeout: c.GetDuration("FETCH_TIMEOUT"),
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = filepath.Join(os.TempDir(), "imageprocessor-uploads")
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = defaultUploadMaxSize
+	}
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = defaultUploadSessionTTL
+	}
+	if cfg.FetchMaxSize <= 0 {
+		cfg.FetchMaxSize = defaultFetchMaxSize
+	}
+	if cfg.FetchTimeout <= 0 {
+		cfg.FetchTimeout = defaultFetchTimeout
+	}
+	return cfg
+}
+
+var defaultPresets = []model.Preset{
+	{Name: "small", Width: 150, Height: 150, Mode: "fill"},
+	{Name: "medium", Width: 400, Height: 400, Mode: "fill"},
+	{Name: "large", Width: 1024, Height: 1024, Mode: "fit"},
+}
+
+// loadThumbnails reads the comma separated preset names from THUMBNAIL_PRESETS
+// and every preset from THUMBNAIL_<NAME>_WIDTH, _HEIGHT, _MODE, _FORMAT and
+// _QUALITY. Without THUMBNAIL_PRESETS the built-in presets are used.
+func loadThumbnails(c *configwbf.Config) ThumbnailConfig {
+	cfg := ThumbnailConfig{Default: c.GetString("THUMBNAIL_DEFAULT_PRESET")}
+
+	names := c.GetString("THUMBNAIL_PRESETS")
+	if names == "" {
+		cfg.Presets = defaultPresets
+		return cfg
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		key := func(field string) string {
+			return fmt.Sprintf("THUMBNAIL_%s_%s", strings.ToUpper(name), field)
+		}
+
+		mode := c.GetString(key("MODE"))
+		if mode == "" {
+			mode = "fill"
+		}
+
+		cfg.Presets = append(cfg.Presets, model.Preset{
+			Name:    name,
+			Width:   c.GetInt(key("WIDTH")),
+			Height:  c.GetInt(key("HEIGHT")),
+			Mode:    mode,
+			Format:  c.GetString(key("FORMAT")),
+			Quality: c.GetInt(key("QUALITY")),
+		})
+	}
+	return cfg
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
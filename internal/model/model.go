@@ -2,24 +2,124 @@ package model
 
 import "time"
 
+// Visibility of an image: private images are available to the owner only,
+// unlisted ones to anyone knowing the id, public ones are also listed.
+const (
+	VisibilityPrivate  = "private"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPublic   = "public"
+)
+
+// DefaultTenant owns anonymous uploads and data created before tenants.
+const DefaultTenant = "default"
+
 type ImageInCreate struct {
+	Tenant        string
 	UploadsPath   string
+	Size          int64
 	ProcessedPath string
 	Processed     bool
+	Owner         string
+	Visibility    string
+	// Quota is checked against the usage of the owner when the image is
+	// created.
+	Quota Quota
 }
 
 type ImageInRepo struct {
-	ID            int       `json:"id"`
-	UploadsPath   string    `json:"uploads_path"`
-	ProcessedPath string    `json:"processed_path"`
-	Processed     bool      `json:"processed"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            int               `json:"id"`
+	Tenant        string            `json:"tenant"`
+	UploadsPath   string            `json:"uploads_path"`
+	ProcessedPath string            `json:"processed_path"`
+	Processed     bool              `json:"processed"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Result        *ProcessingResult `json:"result,omitempty"`
+	Owner         string            `json:"owner,omitempty"`
+	Visibility    string            `json:"visibility"`
+	Size          int64             `json:"size"`
+	Tags          []string          `json:"tags"`
+}
+
+// ImageAccess limits listings to public images and images of Owner, All
+// disables the limit.
+type ImageAccess struct {
+	Owner string
+	All   bool
+}
+
+// Sort orders of image listings, ties are ordered by id.
+const (
+	SortCreatedAt = "created_at"
+	SortID        = "id"
+	SortSize      = "size"
+)
+
+// Statuses of an image in listings.
+const (
+	StatusProcessed = "processed"
+	StatusPending   = "pending"
+)
+
+// ImageFilter selects a page of images. Zero fields do not filter, From is
+// inclusive and To exclusive, Extensions match the end of the uploads path.
+// After continues the listing behind the image it was taken from.
+type ImageFilter struct {
+	Access     ImageAccess
+	Owner      string
+	Tag        string
+	AlbumID    int
+	Status     string
+	Extensions []string
+	From       time.Time
+	To         time.Time
+	Sort       string
+	Desc       bool
+	After      *ImageCursor
+	Limit      int
+}
+
+// ImageCursor is the position of an image in a listing.
+type ImageCursor struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	Size      int64     `json:"size,omitempty"`
+}
+
+type Derivative struct {
+	ID             int               `json:"id"`
+	ImageID        int               `json:"image_id"`
+	TypeProcessing string            `json:"type_processing"`
+	ProcessedPath  string            `json:"processed_path"`
+	Processed      bool              `json:"processed"`
+	CreatedAt      time.Time         `json:"created_at"`
+	Result         *ProcessingResult `json:"result,omitempty"`
+}
+
+type ProcessingResult struct {
+	Redactions []Redaction `json:"redactions,omitempty"`
+	TrimBox    *Rect       `json:"trim_box,omitempty"`
+}
+
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
 }
 
 type ImageTask struct {
 	ImageID        int              `json:"image_id"`
+	Tenant         string           `json:"tenant,omitempty"`
+	DerivativeID   int              `json:"derivative_id,omitempty"`
 	TypeProcessing string           `json:"type_processing"`
 	UploadsPath    string           `json:"uploads_path"`
+	SourceURL      string           `json:"source_url,omitempty"`
+	Parameters     ProcessingParams `json:"parameters"`
+	Steps          []Step           `json:"steps,omitempty"`
+}
+
+type Step struct {
+	TypeProcessing string           `json:"type_processing"`
 	Parameters     ProcessingParams `json:"parameters"`
 }
 
@@ -30,4 +130,132 @@ type ProcessingParams struct {
 	WatermarkPath *string `json:"watermark_path,omitempty"`
 
 	MaxSize *int `json:"max_size,omitempty"`
+
+	Interpolation *string `json:"interpolation,omitempty"`
+
+	Angle      *float64 `json:"angle,omitempty"`
+	Flip       *string  `json:"flip,omitempty"`
+	Background *string  `json:"background,omitempty"`
+	Expand     *bool    `json:"expand,omitempty"`
+
+	Grayscale  *bool    `json:"grayscale,omitempty"`
+	Sepia      *bool    `json:"sepia,omitempty"`
+	Invert     *bool    `json:"invert,omitempty"`
+	Brightness *float64 `json:"brightness,omitempty"`
+	Contrast   *float64 `json:"contrast,omitempty"`
+	Saturation *float64 `json:"saturation,omitempty"`
+	Hue        *float64 `json:"hue,omitempty"`
+	Gamma      *float64 `json:"gamma,omitempty"`
+
+	Filter  *string   `json:"filter,omitempty"`
+	Radius  *int      `json:"radius,omitempty"`
+	Sigma   *float64  `json:"sigma,omitempty"`
+	Amount  *float64  `json:"amount,omitempty"`
+	Kernel  []float64 `json:"kernel,omitempty"`
+	Sharpen *float64  `json:"sharpen,omitempty"`
+
+	Redactions []Redaction `json:"redactions,omitempty"`
+
+	TrimColor *string `json:"trim_color,omitempty"`
+	Tolerance *int    `json:"tolerance,omitempty"`
+	Padding   *int    `json:"padding,omitempty"`
+
+	BorderWidth  *int    `json:"border_width,omitempty"`
+	BorderColor  *string `json:"border_color,omitempty"`
+	CornerRadius *int    `json:"corner_radius,omitempty"`
+	Circle       *bool   `json:"circle,omitempty"`
+
+	Gravity *string `json:"gravity,omitempty"`
+
+	Preset     *string `json:"preset,omitempty"`
+	ResizeMode *string `json:"resize_mode,omitempty"`
+	Format     *string `json:"format,omitempty"`
+	Quality    *int    `json:"quality,omitempty"`
+}
+
+type Redaction struct {
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Method string `json:"method"`
+
+	BlockSize int    `json:"block_size,omitempty"`
+	Radius    int    `json:"radius,omitempty"`
+	Color     string `json:"color,omitempty"`
+}
+
+type Preset struct {
+	Name    string `json:"name"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Mode    string `json:"mode"`
+	Format  string `json:"format,omitempty"`
+	Quality int    `json:"quality,omitempty"`
+}
+
+type Transformation struct {
+	Tenant        string    `json:"tenant"`
+	Name          string    `json:"name"`
+	Steps         []Step    `json:"steps"`
+	Format        string    `json:"format,omitempty"`
+	Quality       int       `json:"quality,omitempty"`
+	WatermarkPath string    `json:"watermark_path,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ObjectInfo is the metadata of an object in the ImageStore.
+type ObjectInfo struct {
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// APIKey is a stored API key. Only the SHA-256 hash of the key is kept, the
+// prefix identifies the key without revealing it.
+type APIKey struct {
+	ID         int        `json:"id"`
+	Tenant     string     `json:"tenant"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TenantConfig is the configuration of a tenant: the formats it may upload,
+// all formats when empty, and the thumbnail preset used when none is given.
+type TenantConfig struct {
+	ID            string   `json:"id"`
+	Formats       []string `json:"formats,omitempty"`
+	DefaultPreset string   `json:"default_preset,omitempty"`
+}
+
+// Usage is the storage used by the originals of an owner.
+type Usage struct {
+	Bytes  int64 `json:"bytes"`
+	Images int   `json:"images"`
+}
+
+// Quota limits the Usage of an owner, zero values disable a limit.
+type Quota struct {
+	MaxBytes  int64
+	MaxImages int
+}
+
+// Album is an ordered collection of images. ImageIDs are in album order and
+// only filled for a single album.
+type Album struct {
+	ID         int       `json:"id"`
+	Tenant     string    `json:"tenant"`
+	Owner      string    `json:"owner,omitempty"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+	ImageCount int       `json:"image_count"`
+	ImageIDs   []int     `json:"image_ids,omitempty"`
 }
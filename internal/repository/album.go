@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+)
+
+func (s *Storage) CreateAlbum(ctx context.Context, album model.Album) (model.Album, error) {
+	query := `INSERT INTO albums (tenant, owner, name, created_at)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id, created_at`
+	err := s.DB.QueryRowContext(ctx, query, album.Tenant, sql.NullString{String: album.Owner, Valid: album.Owner != ""},
+		album.Name, time.Now()).Scan(&album.ID, &album.CreatedAt)
+	if err != nil {
+		return model.Album{}, err
+	}
+	return album, nil
+}
+
+// GetAlbum returns the album with the ids of its images in album order.
+func (s *Storage) GetAlbum(ctx context.Context, tenant string, id int) (model.Album, error) {
+	query := `SELECT id, tenant, owner, name, created_at,
+					(SELECT COUNT(*) FROM album_images WHERE album_id = albums.id)
+				FROM albums
+				WHERE id=$1 AND tenant=$2`
+	res, err := s.DB.QueryContext(ctx, query, id, tenant)
+	if err != nil {
+		return model.Album{}, err
+	}
+	album, err := scanAlbums(res)
+	if err != nil {
+		return model.Album{}, err
+	}
+	if len(album) == 0 {
+		return model.Album{}, sql.ErrNoRows
+	}
+
+	query = `SELECT image_id
+				FROM album_images
+				WHERE album_id=$1
+				ORDER BY position, image_id`
+	rows, err := s.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return model.Album{}, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	album[0].ImageIDs = make([]int, 0, album[0].ImageCount)
+	for rows.Next() {
+		var imageID int
+		if err := rows.Scan(&imageID); err != nil {
+			return model.Album{}, err
+		}
+		album[0].ImageIDs = append(album[0].ImageIDs, imageID)
+	}
+	return album[0], rows.Err()
+}
+
+// GetAlbums returns the albums of an owner, the empty owner stands for
+// albums created anonymously.
+func (s *Storage) GetAlbums(ctx context.Context, tenant, owner string) ([]model.Album, error) {
+	query := `SELECT id, tenant, owner, name, created_at,
+					(SELECT COUNT(*) FROM album_images WHERE album_id = albums.id)
+				FROM albums
+				WHERE COALESCE(owner, '')=$1 AND tenant=$2
+				ORDER BY id`
+	res, err := s.DB.QueryContext(ctx, query, owner, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return scanAlbums(res)
+}
+
+func (s *Storage) DeleteAlbum(ctx context.Context, tenant string, id int) error {
+	query := `DELETE
+				FROM albums
+				WHERE id=$1 AND tenant=$2`
+	res, err := s.DB.ExecContext(ctx, query, id, tenant)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// AddAlbumImage appends the image to the album, adding it again keeps its
+// position.
+func (s *Storage) AddAlbumImage(ctx context.Context, tenant string, albumID, imageID int) error {
+	query := `INSERT INTO album_images (album_id, image_id, position)
+				SELECT a.id, i.id, COALESCE((SELECT MAX(position) FROM album_images WHERE album_id = a.id), 0) + 1
+				FROM albums a, image_path i
+				WHERE a.id=$1 AND a.tenant=$3
+					AND i.id=$2 AND i.tenant=$3
+				ON CONFLICT (album_id, image_id) DO NOTHING`
+	_, err := s.DB.ExecContext(ctx, query, albumID, imageID, tenant)
+	return err
+}
+
+// RemoveAlbumImage returns sql.ErrNoRows when the image is not in the album.
+func (s *Storage) RemoveAlbumImage(ctx context.Context, tenant string, albumID, imageID int) error {
+	query := `DELETE FROM album_images ai
+				USING albums a
+				WHERE ai.album_id = a.id
+					AND a.id=$1 AND a.tenant=$3
+					AND ai.image_id=$2`
+	res, err := s.DB.ExecContext(ctx, query, albumID, imageID, tenant)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+func checkRowsAffected(res sql.Result) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanAlbums(rows *sql.Rows) ([]model.Album, error) {
+	defer func() {
+		if err := rows.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	albums := make([]model.Album, 0)
+	for rows.Next() {
+		var album model.Album
+		var owner sql.NullString
+		err := rows.Scan(&album.ID, &album.Tenant, &owner, &album.Name, &album.CreatedAt, &album.ImageCount)
+		if err != nil {
+			return nil, err
+		}
+		album.Owner = owner.String
+		albums = append(albums, album)
+	}
+	return albums, rows.Err()
+}
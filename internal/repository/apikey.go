@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+)
+
+func (s *Storage) CreateAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error) {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	query := `INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, created_at, tenant)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id, created_at`
+	res := s.DB.QueryRowContext(ctx, query, key.UserID, key.Name, key.Prefix, key.KeyHash, string(scopes), time.Now(), key.Tenant)
+	err = res.Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+	return key, nil
+}
+
+// GetAPIKey returns sql.ErrNoRows when there is no key with the id.
+func (s *Storage) GetAPIKey(ctx context.Context, tenant string, id int) (model.APIKey, error) {
+	query := `SELECT id, user_id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, tenant
+				FROM api_keys
+				WHERE id=$1 AND tenant=$2`
+	return s.getAPIKey(ctx, query, id, tenant)
+}
+
+// GetAPIKeyByPrefix returns sql.ErrNoRows when there is no key with the
+// prefix.
+func (s *Storage) GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	query := `SELECT id, user_id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, tenant
+				FROM api_keys
+				WHERE prefix=$1`
+	return s.getAPIKey(ctx, query, prefix)
+}
+
+func (s *Storage) getAPIKey(ctx context.Context, query string, args ...interface{}) (model.APIKey, error) {
+	res, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	if !res.Next() {
+		if err := res.Err(); err != nil {
+			return model.APIKey{}, err
+		}
+		return model.APIKey{}, sql.ErrNoRows
+	}
+	return scanAPIKey(res)
+}
+
+// GetAPIKeys returns the keys of a user, revoked keys included.
+func (s *Storage) GetAPIKeys(ctx context.Context, tenant, userID string) ([]model.APIKey, error) {
+	query := `SELECT id, user_id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, tenant
+				FROM api_keys
+				WHERE user_id=$1 AND tenant=$2
+				ORDER BY id`
+	res, err := s.DB.QueryContext(ctx, query, userID, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	keys := make([]model.APIKey, 0)
+	for res.Next() {
+		key, err := scanAPIKey(res)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, res.Err()
+}
+
+// RotateAPIKey replaces the secret of an active key.
+func (s *Storage) RotateAPIKey(ctx context.Context, tenant string, id int, prefix, keyHash string) error {
+	query := `UPDATE api_keys
+				SET prefix=$1,
+					key_hash=$2,
+					last_used_at=NULL
+				WHERE id=$3 AND tenant=$4 AND revoked_at IS NULL`
+	return s.execAPIKey(ctx, query, prefix, keyHash, id, tenant)
+}
+
+func (s *Storage) RevokeAPIKey(ctx context.Context, tenant string, id int) error {
+	query := `UPDATE api_keys
+				SET revoked_at=$1
+				WHERE id=$2 AND tenant=$3 AND revoked_at IS NULL`
+	return s.execAPIKey(ctx, query, time.Now(), id, tenant)
+}
+
+func (s *Storage) TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error {
+	query := `UPDATE api_keys
+				SET last_used_at=$1
+				WHERE id=$2`
+	return s.execAPIKey(ctx, query, usedAt, id)
+}
+
+func (s *Storage) execAPIKey(ctx context.Context, query string, args ...interface{}) error {
+	res, err := s.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanAPIKey(rows *sql.Rows) (model.APIKey, error) {
+	var key model.APIKey
+	var scopes []byte
+	var lastUsedAt, revokedAt sql.NullTime
+	err := rows.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &scopes, &key.CreatedAt, &lastUsedAt, &revokedAt, &key.Tenant)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	err = json.Unmarshal(scopes, &key.Scopes)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
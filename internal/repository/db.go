@@ -3,6 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/wb-go/wbf/dbpg"
@@ -11,14 +15,50 @@ import (
 	"ImageProcessor/internal/model"
 )
 
+// Storager methods only see the rows of the given tenant, values created or
+// updated from a model carry the tenant in the model. GetAPIKeyByPrefix and
+// TouchAPIKey serve authentication, before the tenant is known.
 type Storager interface {
 	CreateImage(ctx context.Context, img model.ImageInCreate) (int, error)
-	GetImage(ctx context.Context, id int) (model.ImageInRepo, error)
-	UpdateImage(ctx context.Context, img model.ImageInRepo) error
-	DeleteImage(ctx context.Context, id int) error
+	GetImage(ctx context.Context, tenant string, id int) (model.ImageInRepo, error)
+	UpdateImage(ctx context.Context, tenant string, img model.ImageInRepo) error
+	SetImageSize(ctx context.Context, tenant string, id int, size int64) error
+	DeleteImage(ctx context.Context, tenant string, id int) error
+	UpdateVisibility(ctx context.Context, tenant string, id int, visibility string) error
 
-	GetImages(ctx context.Context, lastCreatedAt time.Time, lastID int, mode string) ([]model.ImageInRepo, error)
-	GetCountImages(ctx context.Context) (int, error)
+	GetImages(ctx context.Context, tenant string, filter model.ImageFilter) ([]model.ImageInRepo, error)
+	GetCountImages(ctx context.Context, tenant string, filter model.ImageFilter) (int, error)
+
+	CreateDerivative(ctx context.Context, tenant string, d model.Derivative) (int, error)
+	UpdateDerivative(ctx context.Context, tenant string, d model.Derivative) error
+	GetDerivatives(ctx context.Context, tenant string, imageID int) ([]model.Derivative, error)
+	DeleteDerivative(ctx context.Context, tenant string, id int) error
+
+	CreateTransformation(ctx context.Context, t model.Transformation) (model.Transformation, error)
+	GetTransformation(ctx context.Context, tenant, name string) (model.Transformation, error)
+	GetTransformations(ctx context.Context, tenant string) ([]model.Transformation, error)
+	UpdateTransformation(ctx context.Context, t model.Transformation) (model.Transformation, error)
+	DeleteTransformation(ctx context.Context, tenant, name string) error
+
+	CreateAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error)
+	GetAPIKey(ctx context.Context, tenant string, id int) (model.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error)
+	GetAPIKeys(ctx context.Context, tenant, userID string) ([]model.APIKey, error)
+	RotateAPIKey(ctx context.Context, tenant string, id int, prefix, keyHash string) error
+	RevokeAPIKey(ctx context.Context, tenant string, id int) error
+	TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error
+
+	GetUsage(ctx context.Context, tenant, owner string) (model.Usage, error)
+
+	TagImage(ctx context.Context, tenant string, imageID int, tag string) error
+	UntagImage(ctx context.Context, tenant string, imageID int, tag string) error
+
+	CreateAlbum(ctx context.Context, album model.Album) (model.Album, error)
+	GetAlbum(ctx context.Context, tenant string, id int) (model.Album, error)
+	GetAlbums(ctx context.Context, tenant, owner string) ([]model.Album, error)
+	DeleteAlbum(ctx context.Context, tenant string, id int) error
+	AddAlbumImage(ctx context.Context, tenant string, albumID, imageID int) error
+	RemoveAlbumImage(ctx context.Context, tenant string, albumID, imageID int) error
 
 	Close() error
 }
@@ -45,60 +85,152 @@ func NewStorage(dsn string) (*Storage, error) {
 	return &Storage{DB: db}, nil
 }
 
-func (s *Storage) DeleteImage(ctx context.Context, id int) error {
-	query := `DELETE 
-				FROM image_path
-				WHERE id=$1`
-	res, err := s.DB.ExecContext(ctx, query, id)
+// DeleteImage also releases the storage used by the original.
+func (s *Storage) DeleteImage(ctx context.Context, tenant string, id int) error {
+	query := `WITH deleted AS (
+					DELETE
+					FROM image_path
+					WHERE id=$1 AND tenant=$2
+					RETURNING tenant, COALESCE(owner, '') AS owner, size
+				), released AS (
+					UPDATE storage_usage u
+					SET bytes = GREATEST(u.bytes - d.size, 0),
+						images = GREATEST(u.images - 1, 0)
+					FROM deleted d
+					WHERE u.tenant = d.tenant AND u.owner = d.owner
+				)
+				SELECT COUNT(*) FROM deleted`
+	var count int
+	err := s.DB.QueryRowContext(ctx, query, id, tenant).Scan(&count)
 	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (s *Storage) UpdateImage(ctx context.Context, tenant string, img model.ImageInRepo) error {
+	var result sql.NullString
+	if img.Result != nil {
+		data, err := json.Marshal(img.Result)
+		if err != nil {
+			return err
+		}
+		result = sql.NullString{String: string(data), Valid: true}
+	}
+
+	query := `UPDATE image_path
+				SET processed_path=$1,
+					processed=$2,
+					result=$3
+				WHERE id=$4 AND tenant=$5`
+	_, err := s.DB.ExecContext(ctx, query, img.ProcessedPath, img.Processed, result, img.ID, tenant)
 	if err != nil {
 		return err
 	}
+	return nil
+}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+// SetImageSize records the size of an original stored after the image was
+// created and moves the storage usage of its owner by the difference.
+func (s *Storage) SetImageSize(ctx context.Context, tenant string, id int, size int64) error {
+	query := `WITH old AS (
+					SELECT tenant, COALESCE(owner, '') AS owner, size
+					FROM image_path
+					WHERE id=$1 AND tenant=$2
+					FOR UPDATE
+				), updated AS (
+					UPDATE image_path
+					SET size=$3
+					WHERE id=$1 AND tenant=$2
+					RETURNING 1
+				), usage AS (
+					UPDATE storage_usage u
+					SET bytes = GREATEST(u.bytes + $3 - o.size, 0)
+					FROM old o
+					WHERE u.tenant = o.tenant AND u.owner = o.owner
+				)
+				SELECT COUNT(*) FROM updated`
+	var count int
+	err := s.DB.QueryRowContext(ctx, query, id, tenant, size).Scan(&count)
+	if err != nil {
+		return err
 	}
 
+	if count == 0 {
+		return sql.ErrNoRows
+	}
 	return nil
 }
 
-func (s *Storage) UpdateImage(ctx context.Context, img model.ImageInRepo) error {
+func (s *Storage) UpdateVisibility(ctx context.Context, tenant string, id int, visibility string) error {
 	query := `UPDATE image_path
-				SET processed_path=$1,
-					processed=$2
-				WHERE id=$3`
-	_, err := s.DB.ExecContext(ctx, query, img.ProcessedPath, img.Processed, img.ID)
+				SET visibility=$1
+				WHERE id=$2 AND tenant=$3`
+	res, err := s.DB.ExecContext(ctx, query, visibility, id, tenant)
 	if err != nil {
 		return err
 	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
 	return nil
 }
 
+// CreateImage counts the image in the storage usage of its owner and
+// returns ErrQuotaExceeded without creating it when img.Quota is exceeded.
 func (s *Storage) CreateImage(ctx context.Context, img model.ImageInCreate) (int, error) {
-	query := `INSERT INTO image_path (uploads_path, processed_path, processed, created_at)
-				VALUES ($1, $2, $3, $4)
+	query := `WITH usage AS (
+					INSERT INTO storage_usage AS u (tenant, owner, bytes, images)
+					SELECT $7, COALESCE($5, ''), $8, 1
+					WHERE ($9::BIGINT = 0 OR $8 <= $9::BIGINT)
+					ON CONFLICT (tenant, owner) DO UPDATE
+					SET bytes = u.bytes + EXCLUDED.bytes,
+						images = u.images + 1
+					WHERE ($9::BIGINT = 0 OR u.bytes + EXCLUDED.bytes <= $9::BIGINT)
+						AND ($10::INTEGER = 0 OR u.images < $10::INTEGER)
+					RETURNING 1
+				)
+				INSERT INTO image_path (uploads_path, processed_path, processed, created_at, owner, visibility, tenant, size)
+				SELECT $1, $2, $3, $4, $5, $6, $7, $8
+				FROM usage
 				RETURNING id`
 	var id int
-	res := s.DB.QueryRowContext(ctx, query, img.UploadsPath, img.ProcessedPath, img.Processed, time.Now())
+	res := s.DB.QueryRowContext(ctx, query, img.UploadsPath, img.ProcessedPath, img.Processed, time.Now(),
+		sql.NullString{String: img.Owner, Valid: img.Owner != ""}, img.Visibility, img.Tenant, img.Size,
+		img.Quota.MaxBytes, img.Quota.MaxImages)
 	if res.Err() != nil {
 		return 0, res.Err()
 	}
 	err := res.Scan(&id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrQuotaExceeded
+		}
 		return 0, err
 	}
 	return id, nil
 }
 
-func (s *Storage) GetImage(ctx context.Context, id int) (model.ImageInRepo, error) {
-	query := `SELECT id, uploads_path, processed_path, processed, created_at
+func (s *Storage) GetImage(ctx context.Context, tenant string, id int) (model.ImageInRepo, error) {
+	query := `SELECT id, uploads_path, processed_path, processed, created_at, result, owner, visibility, tenant, size,
+					(SELECT COALESCE(json_agg(t.name ORDER BY t.name), '[]')
+						FROM image_tags it
+						JOIN tags t ON t.id = it.tag_id
+						WHERE it.image_id = image_path.id) AS tags
 				FROM image_path
-				WHERE id=$1`
-	res, err := s.DB.QueryContext(ctx, query, id)
+				WHERE id=$1 AND tenant=$2`
+	res, err := s.DB.QueryContext(ctx, query, id, tenant)
 	if err != nil {
 		return model.ImageInRepo{}, err
 	}
@@ -111,7 +243,7 @@ func (s *Storage) GetImage(ctx context.Context, id int) (model.ImageInRepo, erro
 
 	var img model.ImageInRepo
 	if res.Next() {
-		err := res.Scan(&img.ID, &img.UploadsPath, &img.ProcessedPath, &img.Processed, &img.CreatedAt)
+		img, err = scanImage(res)
 		if err != nil {
 			return model.ImageInRepo{}, err
 		}
@@ -119,28 +251,58 @@ func (s *Storage) GetImage(ctx context.Context, id int) (model.ImageInRepo, erro
 	return img, nil
 }
 
-func (s *Storage) GetImages(ctx context.Context, lastCreatedAt time.Time, lastID int, mode string) ([]model.ImageInRepo, error) {
-	var query string
-	var args []interface{}
+// imageSortColumns are the columns GetImages may order by.
+var imageSortColumns = map[string]string{
+	model.SortCreatedAt: "created_at",
+	model.SortID:        "id",
+	model.SortSize:      "size",
+}
 
-	switch mode {
-	case "next":
-		query = `SELECT id, uploads_path, processed_path, processed, created_at
-                FROM image_path
-                WHERE created_at > $1 AND id > $2
-                ORDER BY created_at ASC, id ASC
-                LIMIT 4`
-		args = []interface{}{lastCreatedAt, lastID}
+// GetImages returns up to filter.Limit images in the order of filter.Sort.
+// The page starts behind filter.After, compared as (column, id) so that
+// images with equal values are neither skipped nor repeated.
+func (s *Storage) GetImages(ctx context.Context, tenant string, filter model.ImageFilter) ([]model.ImageInRepo, error) {
+	column, ok := imageSortColumns[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort %q", filter.Sort)
+	}
+	direction, compare := "ASC", ">"
+	if filter.Desc {
+		direction, compare = "DESC", "<"
+	}
 
-	case "prev":
-		query = `SELECT id, uploads_path, processed_path, processed, created_at
-                FROM image_path
-                WHERE (created_at < $1) OR (created_at = $1 AND id < $2)
-                ORDER BY created_at DESC, id DESC
-                LIMIT 4`
-		args = []interface{}{lastCreatedAt, lastID}
+	where, args := imageConditions(tenant, filter)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
 	}
 
+	if filter.After != nil {
+		switch filter.Sort {
+		case model.SortID:
+			where = append(where, fmt.Sprintf("id %s %s", compare, arg(filter.After.ID)))
+		case model.SortCreatedAt:
+			where = append(where, fmt.Sprintf("(created_at, id) %s (%s, %s)", compare, arg(filter.After.CreatedAt), arg(filter.After.ID)))
+		case model.SortSize:
+			where = append(where, fmt.Sprintf("(size, id) %s (%s, %s)", compare, arg(filter.After.Size), arg(filter.After.ID)))
+		}
+	}
+
+	order := "id " + direction
+	if column != "id" {
+		order = fmt.Sprintf("%s %s, id %s", column, direction, direction)
+	}
+
+	query := fmt.Sprintf(`SELECT id, uploads_path, processed_path, processed, created_at, result, owner, visibility, tenant, size,
+					(SELECT COALESCE(json_agg(t.name ORDER BY t.name), '[]')
+						FROM image_tags it
+						JOIN tags t ON t.id = it.tag_id
+						WHERE it.image_id = image_path.id) AS tags
+                FROM image_path
+                WHERE %s
+                ORDER BY %s
+                LIMIT %s`, strings.Join(where, " AND "), order, arg(filter.Limit))
+
 	res, err := s.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -154,19 +316,91 @@ func (s *Storage) GetImages(ctx context.Context, lastCreatedAt time.Time, lastID
 
 	images := make([]model.ImageInRepo, 0)
 	for res.Next() {
-		var temp model.ImageInRepo
-		err := res.Scan(&temp.ID, &temp.UploadsPath, &temp.ProcessedPath, &temp.Processed, &temp.CreatedAt)
+		temp, err := scanImage(res)
 		if err != nil {
 			return nil, err
 		}
 		images = append(images, temp)
 	}
-	return images, nil
+	return images, res.Err()
 }
 
-func (s *Storage) GetCountImages(ctx context.Context) (int, error) {
-	query := `SELECT COUNT(*) FROM image_path`
-	res := s.DB.QueryRowContext(ctx, query)
+// imageConditions returns the WHERE conditions of the filter without the
+// cursor and their arguments.
+func imageConditions(tenant string, filter model.ImageFilter) ([]string, []any) {
+	args := []any{tenant, filter.Access.All, filter.Access.Owner}
+	where := []string{"tenant = $1", "($2 OR visibility = 'public' OR owner = $3)"}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Owner != "" {
+		where = append(where, "owner = "+arg(filter.Owner))
+	}
+	if filter.Tag != "" {
+		where = append(where, fmt.Sprintf(`id IN (SELECT it.image_id
+					FROM image_tags it
+					JOIN tags t ON t.id = it.tag_id
+					WHERE t.tenant = $1 AND t.name = %s)`, arg(filter.Tag)))
+	}
+	if filter.AlbumID != 0 {
+		where = append(where, "id IN (SELECT image_id FROM album_images WHERE album_id = "+arg(filter.AlbumID)+")")
+	}
+	switch filter.Status {
+	case model.StatusProcessed:
+		where = append(where, "processed")
+	case model.StatusPending:
+		where = append(where, "NOT processed")
+	}
+	if len(filter.Extensions) > 0 {
+		var exts []string
+		for _, ext := range filter.Extensions {
+			exts = append(exts, "lower(uploads_path) LIKE "+arg("%"+strings.ToLower(ext)))
+		}
+		where = append(where, "("+strings.Join(exts, " OR ")+")")
+	}
+	if !filter.From.IsZero() {
+		where = append(where, "created_at >= "+arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		where = append(where, "created_at < "+arg(filter.To))
+	}
+	return where, args
+}
+
+func scanImage(rows *sql.Rows) (model.ImageInRepo, error) {
+	var img model.ImageInRepo
+	var result, tags []byte
+	var owner sql.NullString
+	err := rows.Scan(&img.ID, &img.UploadsPath, &img.ProcessedPath, &img.Processed, &img.CreatedAt, &result, &owner, &img.Visibility, &img.Tenant, &img.Size, &tags)
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+	img.Owner = owner.String
+
+	err = json.Unmarshal(tags, &img.Tags)
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	if len(result) > 0 {
+		err = json.Unmarshal(result, &img.Result)
+		if err != nil {
+			return model.ImageInRepo{}, err
+		}
+	}
+	return img, nil
+}
+
+// GetCountImages counts all images matching the filter, the cursor, the
+// order and the limit are ignored.
+func (s *Storage) GetCountImages(ctx context.Context, tenant string, filter model.ImageFilter) (int, error) {
+	where, args := imageConditions(tenant, filter)
+	query := `SELECT COUNT(*)
+				FROM image_path
+				WHERE ` + strings.Join(where, " AND ")
+	res := s.DB.QueryRowContext(ctx, query, args...)
 	if err := res.Err(); err != nil {
 		return 0, err
 	}
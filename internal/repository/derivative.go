@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+)
+
+// CreateDerivative returns sql.ErrNoRows when the image is not in the tenant.
+func (s *Storage) CreateDerivative(ctx context.Context, tenant string, d model.Derivative) (int, error) {
+	query := `INSERT INTO image_derivatives (image_id, type_processing, processed_path, processed, created_at)
+				SELECT id, $2, $3, $4, $5
+				FROM image_path
+				WHERE id=$1 AND tenant=$6
+				RETURNING id`
+	var id int
+	res := s.DB.QueryRowContext(ctx, query, d.ImageID, d.TypeProcessing, d.ProcessedPath, d.Processed, time.Now(), tenant)
+	err := res.Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *Storage) UpdateDerivative(ctx context.Context, tenant string, d model.Derivative) error {
+	var result sql.NullString
+	if d.Result != nil {
+		data, err := json.Marshal(d.Result)
+		if err != nil {
+			return err
+		}
+		result = sql.NullString{String: string(data), Valid: true}
+	}
+
+	query := `UPDATE image_derivatives
+				SET processed_path=$1,
+					processed=$2,
+					result=$3
+				WHERE id=$4
+					AND image_id IN (SELECT id FROM image_path WHERE tenant=$5)`
+	_, err := s.DB.ExecContext(ctx, query, d.ProcessedPath, d.Processed, result, d.ID, tenant)
+	return err
+}
+
+func (s *Storage) GetDerivatives(ctx context.Context, tenant string, imageID int) ([]model.Derivative, error) {
+	query := `SELECT d.id, d.image_id, d.type_processing, d.processed_path, d.processed, d.created_at, d.result
+				FROM image_derivatives d
+				JOIN image_path i ON i.id = d.image_id
+				WHERE d.image_id=$1 AND i.tenant=$2
+				ORDER BY d.created_at, d.id`
+	res, err := s.DB.QueryContext(ctx, query, imageID, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	derivatives := make([]model.Derivative, 0)
+	for res.Next() {
+		var d model.Derivative
+		var result []byte
+		err := res.Scan(&d.ID, &d.ImageID, &d.TypeProcessing, &d.ProcessedPath, &d.Processed, &d.CreatedAt, &result)
+		if err != nil {
+			return nil, err
+		}
+		if len(result) > 0 {
+			err = json.Unmarshal(result, &d.Result)
+			if err != nil {
+				return nil, err
+			}
+		}
+		derivatives = append(derivatives, d)
+	}
+	return derivatives, res.Err()
+}
+
+// DeleteDerivative removes the row of a derivative whose task was not
+// published.
+func (s *Storage) DeleteDerivative(ctx context.Context, tenant string, id int) error {
+	query := `DELETE FROM image_derivatives
+				WHERE id=$1
+					AND image_id IN (SELECT id FROM image_path WHERE tenant=$2)`
+	_, err := s.DB.ExecContext(ctx, query, id, tenant)
+	return err
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"mime"
 	"path/filepath"
+	"regexp"
 	"sync"
 	"time"
 
@@ -18,16 +19,54 @@ type ImageStore interface {
 	Upload(ctx context.Context, file io.Reader, objectName string, size int64) error
 	Download(ctx context.Context, objectName string) (io.ReadCloser, error)
 	Delete(ctx context.Context, objectName string) error
-	GetManyURL(ctx context.Context, images []model.ImageInRepo, expiry time.Duration) ([]string, error)
-	GetURL(ctx context.Context, image model.ImageInRepo) (string, error)
+	DeletePrefix(ctx context.Context, prefix string) error
+	Exists(ctx context.Context, objectName string) (bool, error)
+	Open(ctx context.Context, objectName string) (io.ReadSeekCloser, model.ObjectInfo, error)
+	GetManyURL(ctx context.Context, objectNames []string, expiry time.Duration) ([]string, error)
+	GetURL(ctx context.Context, objectName string, expiry time.Duration) (string, error)
 }
 
+var ErrObjectNotFound = fmt.Errorf("object not found")
+
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ValidTenant reports whether id can be used as a tenant id and a part of
+// object names.
+func ValidTenant(id string) bool {
+	return tenantPattern.MatchString(id)
+}
+
+// TenantKey returns the object name of objectName in the tenant. Objects of
+// the default tenant keep their names, other tenants live under
+// tenants/<tenant>/.
+func TenantKey(tenant, objectName string) string {
+	if tenant == "" || tenant == model.DefaultTenant {
+		return objectName
+	}
+	return "tenants/" + tenant + "/" + objectName
+}
+
+// StreamPartSize is the part size of uploads of unknown size. Without it
+// minio-go buffers parts sized for the largest possible object, about 528 MiB
+// each.
+const StreamPartSize = 16 << 20
+
+// presignRegion is used by the client that signs URLs for the public
+// endpoint, so signing does not need to reach it.
+const presignRegion = "us-east-1"
+
 type ImageStorage struct {
 	Client     *minio.Client
 	BucketName string
+	// PresignClient signs URLs for the endpoint clients can reach.
+	PresignClient *minio.Client
 }
 
-func NewImageStorage(endpoint, user, password, bucketName string, sslMode bool) (*ImageStorage, error) {
+// NewImageStorage creates the bucket if needed. With publicRead the bucket is
+// readable by anyone, otherwise its policy is removed and objects are only
+// available through presigned URLs. Presigned URLs point to publicEndpoint,
+// an empty value selects endpoint.
+func NewImageStorage(endpoint, user, password, bucketName string, sslMode bool, publicEndpoint string, publicRead bool) (*ImageStorage, error) {
 	ctx := context.Background()
 
 	client, err := minio.New(endpoint, &minio.Options{
@@ -51,7 +90,9 @@ func NewImageStorage(endpoint, user, password, bucketName string, sslMode bool)
 		}
 	}
 
-	policy := fmt.Sprintf(`{
+	policy := ""
+	if publicRead {
+		policy = fmt.Sprintf(`{
         "Version": "2012-10-17",
         "Statement": [
             {
@@ -64,13 +105,26 @@ func NewImageStorage(endpoint, user, password, bucketName string, sslMode bool)
             }
         ]
     }`, bucketName)
+	}
 
 	err = client.SetBucketPolicy(ctx, bucketName, policy)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ImageStorage{Client: client, BucketName: bucketName}, nil
+	presignClient := client
+	if publicEndpoint != "" && publicEndpoint != endpoint {
+		presignClient, err = minio.New(publicEndpoint, &minio.Options{
+			Creds:  credentials.NewStaticV4(user, password, ""),
+			Secure: sslMode,
+			Region: presignRegion,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ImageStorage{Client: client, BucketName: bucketName, PresignClient: presignClient}, nil
 }
 
 func (i *ImageStorage) Upload(ctx context.Context, file io.Reader, objectName string, size int64) error {
@@ -83,9 +137,12 @@ func (i *ImageStorage) Upload(ctx context.Context, file io.Reader, objectName st
 		}
 	}
 
-	_, err := i.Client.PutObject(ctx, i.BucketName, objectName, file, size, minio.PutObjectOptions{
-		ContentType: contentType,
-	})
+	opts := minio.PutObjectOptions{ContentType: contentType}
+	if size < 0 {
+		opts.PartSize = StreamPartSize
+	}
+
+	_, err := i.Client.PutObject(ctx, i.BucketName, objectName, file, size, opts)
 	if err != nil {
 		return err
 	}
@@ -110,33 +167,93 @@ func (i *ImageStorage) Delete(ctx context.Context, objectName string) error {
 	return nil
 }
 
-func (i *ImageStorage) GetManyURL(ctx context.Context, images []model.ImageInRepo, expiry time.Duration) ([]string, error) {
-	urls := make([]string, len(images))
-	errCh := make(chan error, len(images))
+// DeletePrefix removes every object whose name starts with prefix.
+func (i *ImageStorage) DeletePrefix(ctx context.Context, prefix string) error {
+	var listErr error
+	objects := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objects)
+		for object := range i.Client.ListObjects(ctx, i.BucketName, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+			if object.Err != nil {
+				listErr = object.Err
+				return
+			}
+			select {
+			case objects <- object:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var err error
+	for res := range i.Client.RemoveObjects(ctx, i.BucketName, objects, minio.RemoveObjectsOptions{}) {
+		if res.Err != nil && err == nil {
+			err = res.Err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return listErr
+}
+
+func (i *ImageStorage) Exists(ctx context.Context, objectName string) (bool, error) {
+	_, err := i.Client.StatObject(ctx, i.BucketName, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Open returns a seekable reader of the object together with its metadata.
+func (i *ImageStorage) Open(ctx context.Context, objectName string) (io.ReadSeekCloser, model.ObjectInfo, error) {
+	file, err := i.Client.GetObject(ctx, i.BucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, model.ObjectInfo{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, model.ObjectInfo{}, ErrObjectNotFound
+		}
+		return nil, model.ObjectInfo{}, err
+	}
+
+	return file, model.ObjectInfo{
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+func (i *ImageStorage) GetManyURL(ctx context.Context, objectNames []string, expiry time.Duration) ([]string, error) {
+	urls := make([]string, len(objectNames))
+	errCh := make(chan error, len(objectNames))
 
 	var wg sync.WaitGroup
-	_, cancel := context.WithCancel(ctx)
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	for idx, img := range images {
+	for idx, objectName := range objectNames {
 		wg.Add(1)
-		go func(index int, processedPath, uploadsPath string) {
+		go func(index int, objectName string) {
 			defer wg.Done()
-			var objectName string
-			if processedPath == "" {
-				objectName = uploadsPath
-			} else {
-				objectName = processedPath
-			}
 
-			url, err := i.Client.PresignedGetObject(ctx, i.BucketName, objectName, expiry, nil)
+			url, err := i.GetURL(ctx, objectName, expiry)
 			if err != nil {
 				errCh <- err
 				cancel()
 				return
 			}
-			urls[idx] = url.String()
-		}(idx, img.ProcessedPath, img.UploadsPath)
+			urls[index] = url
+		}(idx, objectName)
 	}
 
 	go func() {
@@ -158,14 +275,8 @@ func (i *ImageStorage) GetManyURL(ctx context.Context, images []model.ImageInRep
 
 }
 
-func (i *ImageStorage) GetURL(ctx context.Context, image model.ImageInRepo) (string, error) {
-	var objectName string
-	if image.ProcessedPath == "" {
-		objectName = image.UploadsPath
-	} else {
-		objectName = image.ProcessedPath
-	}
-	url, err := i.Client.PresignedGetObject(ctx, i.BucketName, objectName, 7*time.Hour, nil)
+func (i *ImageStorage) GetURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	url, err := i.PresignClient.PresignedGetObject(ctx, i.BucketName, objectName, expiry, nil)
 	if err != nil {
 		return "", err
 	}
@@ -97,6 +97,63 @@ func (_c *MockImageStore_Delete_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
+// DeletePrefix provides a mock function for the type MockImageStore
+func (_mock *MockImageStore) DeletePrefix(ctx context.Context, prefix string) error {
+	ret := _mock.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePrefix")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, prefix)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockImageStore_DeletePrefix_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePrefix'
+type MockImageStore_DeletePrefix_Call struct {
+	*mock.Call
+}
+
+// DeletePrefix is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+func (_e *MockImageStore_Expecter) DeletePrefix(ctx interface{}, prefix interface{}) *MockImageStore_DeletePrefix_Call {
+	return &MockImageStore_DeletePrefix_Call{Call: _e.mock.On("DeletePrefix", ctx, prefix)}
+}
+
+func (_c *MockImageStore_DeletePrefix_Call) Run(run func(ctx context.Context, prefix string)) *MockImageStore_DeletePrefix_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockImageStore_DeletePrefix_Call) Return(err error) *MockImageStore_DeletePrefix_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockImageStore_DeletePrefix_Call) RunAndReturn(run func(ctx context.Context, prefix string) error) *MockImageStore_DeletePrefix_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Download provides a mock function for the type MockImageStore
 func (_mock *MockImageStore) Download(ctx context.Context, objectName string) (io.ReadCloser, error) {
 	ret := _mock.Called(ctx, objectName)
@@ -165,9 +222,75 @@ func (_c *MockImageStore_Download_Call) RunAndReturn(run func(ctx context.Contex
 	return _c
 }
 
+// Exists provides a mock function for the type MockImageStore
+func (_mock *MockImageStore) Exists(ctx context.Context, objectName string) (bool, error) {
+	ret := _mock.Called(ctx, objectName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exists")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(ctx, objectName)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(ctx, objectName)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, objectName)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockImageStore_Exists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exists'
+type MockImageStore_Exists_Call struct {
+	*mock.Call
+}
+
+// Exists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - objectName string
+func (_e *MockImageStore_Expecter) Exists(ctx interface{}, objectName interface{}) *MockImageStore_Exists_Call {
+	return &MockImageStore_Exists_Call{Call: _e.mock.On("Exists", ctx, objectName)}
+}
+
+func (_c *MockImageStore_Exists_Call) Run(run func(ctx context.Context, objectName string)) *MockImageStore_Exists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockImageStore_Exists_Call) Return(b bool, err error) *MockImageStore_Exists_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockImageStore_Exists_Call) RunAndReturn(run func(ctx context.Context, objectName string) (bool, error)) *MockImageStore_Exists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetManyURL provides a mock function for the type MockImageStore
-func (_mock *MockImageStore) GetManyURL(ctx context.Context, images []model.ImageInRepo, expiry time.Duration) ([]string, error) {
-	ret := _mock.Called(ctx, images, expiry)
+func (_mock *MockImageStore) GetManyURL(ctx context.Context, objectNames []string, expiry time.Duration) ([]string, error) {
+	ret := _mock.Called(ctx, objectNames, expiry)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetManyURL")
@@ -175,18 +298,18 @@ func (_mock *MockImageStore) GetManyURL(ctx context.Context, images []model.Imag
 
 	var r0 []string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []model.ImageInRepo, time.Duration) ([]string, error)); ok {
-		return returnFunc(ctx, images, expiry)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, time.Duration) ([]string, error)); ok {
+		return returnFunc(ctx, objectNames, expiry)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []model.ImageInRepo, time.Duration) []string); ok {
-		r0 = returnFunc(ctx, images, expiry)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, time.Duration) []string); ok {
+		r0 = returnFunc(ctx, objectNames, expiry)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []model.ImageInRepo, time.Duration) error); ok {
-		r1 = returnFunc(ctx, images, expiry)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, time.Duration) error); ok {
+		r1 = returnFunc(ctx, objectNames, expiry)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -200,21 +323,21 @@ type MockImageStore_GetManyURL_Call struct {
 
 // GetManyURL is a helper method to define mock.On call
 //   - ctx context.Context
-//   - images []model.ImageInRepo
+//   - objectNames []string
 //   - expiry time.Duration
-func (_e *MockImageStore_Expecter) GetManyURL(ctx interface{}, images interface{}, expiry interface{}) *MockImageStore_GetManyURL_Call {
-	return &MockImageStore_GetManyURL_Call{Call: _e.mock.On("GetManyURL", ctx, images, expiry)}
+func (_e *MockImageStore_Expecter) GetManyURL(ctx interface{}, objectNames interface{}, expiry interface{}) *MockImageStore_GetManyURL_Call {
+	return &MockImageStore_GetManyURL_Call{Call: _e.mock.On("GetManyURL", ctx, objectNames, expiry)}
 }
 
-func (_c *MockImageStore_GetManyURL_Call) Run(run func(ctx context.Context, images []model.ImageInRepo, expiry time.Duration)) *MockImageStore_GetManyURL_Call {
+func (_c *MockImageStore_GetManyURL_Call) Run(run func(ctx context.Context, objectNames []string, expiry time.Duration)) *MockImageStore_GetManyURL_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []model.ImageInRepo
+		var arg1 []string
 		if args[1] != nil {
-			arg1 = args[1].([]model.ImageInRepo)
+			arg1 = args[1].([]string)
 		}
 		var arg2 time.Duration
 		if args[2] != nil {
@@ -234,14 +357,14 @@ func (_c *MockImageStore_GetManyURL_Call) Return(strings []string, err error) *M
 	return _c
 }
 
-func (_c *MockImageStore_GetManyURL_Call) RunAndReturn(run func(ctx context.Context, images []model.ImageInRepo, expiry time.Duration) ([]string, error)) *MockImageStore_GetManyURL_Call {
+func (_c *MockImageStore_GetManyURL_Call) RunAndReturn(run func(ctx context.Context, objectNames []string, expiry time.Duration) ([]string, error)) *MockImageStore_GetManyURL_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // GetURL provides a mock function for the type MockImageStore
-func (_mock *MockImageStore) GetURL(ctx context.Context, image model.ImageInRepo) (string, error) {
-	ret := _mock.Called(ctx, image)
+func (_mock *MockImageStore) GetURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	ret := _mock.Called(ctx, objectName, expiry)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetURL")
@@ -249,16 +372,16 @@ func (_mock *MockImageStore) GetURL(ctx context.Context, image model.ImageInRepo
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.ImageInRepo) (string, error)); ok {
-		return returnFunc(ctx, image)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Duration) (string, error)); ok {
+		return returnFunc(ctx, objectName, expiry)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.ImageInRepo) string); ok {
-		r0 = returnFunc(ctx, image)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Duration) string); ok {
+		r0 = returnFunc(ctx, objectName, expiry)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, model.ImageInRepo) error); ok {
-		r1 = returnFunc(ctx, image)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = returnFunc(ctx, objectName, expiry)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -272,24 +395,30 @@ type MockImageStore_GetURL_Call struct {
 
 // GetURL is a helper method to define mock.On call
 //   - ctx context.Context
-//   - image model.ImageInRepo
-func (_e *MockImageStore_Expecter) GetURL(ctx interface{}, image interface{}) *MockImageStore_GetURL_Call {
-	return &MockImageStore_GetURL_Call{Call: _e.mock.On("GetURL", ctx, image)}
+//   - objectName string
+//   - expiry time.Duration
+func (_e *MockImageStore_Expecter) GetURL(ctx interface{}, objectName interface{}, expiry interface{}) *MockImageStore_GetURL_Call {
+	return &MockImageStore_GetURL_Call{Call: _e.mock.On("GetURL", ctx, objectName, expiry)}
 }
 
-func (_c *MockImageStore_GetURL_Call) Run(run func(ctx context.Context, image model.ImageInRepo)) *MockImageStore_GetURL_Call {
+func (_c *MockImageStore_GetURL_Call) Run(run func(ctx context.Context, objectName string, expiry time.Duration)) *MockImageStore_GetURL_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 model.ImageInRepo
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(model.ImageInRepo)
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -300,7 +429,81 @@ func (_c *MockImageStore_GetURL_Call) Return(s string, err error) *MockImageStor
 	return _c
 }
 
-func (_c *MockImageStore_GetURL_Call) RunAndReturn(run func(ctx context.Context, image model.ImageInRepo) (string, error)) *MockImageStore_GetURL_Call {
+func (_c *MockImageStore_GetURL_Call) RunAndReturn(run func(ctx context.Context, objectName string, expiry time.Duration) (string, error)) *MockImageStore_GetURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Open provides a mock function for the type MockImageStore
+func (_mock *MockImageStore) Open(ctx context.Context, objectName string) (io.ReadSeekCloser, model.ObjectInfo, error) {
+	ret := _mock.Called(ctx, objectName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 io.ReadSeekCloser
+	var r1 model.ObjectInfo
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (io.ReadSeekCloser, model.ObjectInfo, error)); ok {
+		return returnFunc(ctx, objectName)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) io.ReadSeekCloser); ok {
+		r0 = returnFunc(ctx, objectName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadSeekCloser)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) model.ObjectInfo); ok {
+		r1 = returnFunc(ctx, objectName)
+	} else {
+		r1 = ret.Get(1).(model.ObjectInfo)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = returnFunc(ctx, objectName)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockImageStore_Open_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Open'
+type MockImageStore_Open_Call struct {
+	*mock.Call
+}
+
+// Open is a helper method to define mock.On call
+//   - ctx context.Context
+//   - objectName string
+func (_e *MockImageStore_Expecter) Open(ctx interface{}, objectName interface{}) *MockImageStore_Open_Call {
+	return &MockImageStore_Open_Call{Call: _e.mock.On("Open", ctx, objectName)}
+}
+
+func (_c *MockImageStore_Open_Call) Run(run func(ctx context.Context, objectName string)) *MockImageStore_Open_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockImageStore_Open_Call) Return(readSeekCloser io.ReadSeekCloser, objectInfo model.ObjectInfo, err error) *MockImageStore_Open_Call {
+	_c.Call.Return(readSeekCloser, objectInfo, err)
+	return _c
+}
+
+func (_c *MockImageStore_Open_Call) RunAndReturn(run func(ctx context.Context, objectName string) (io.ReadSeekCloser, model.ObjectInfo, error)) *MockImageStore_Open_Call {
 	_c.Call.Return(run)
 	return _c
 }
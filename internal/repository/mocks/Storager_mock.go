@@ -39,6 +39,75 @@ func (_m *MockStorager) EXPECT() *MockStorager_Expecter {
 	return &MockStorager_Expecter{mock: &_m.Mock}
 }
 
+// AddAlbumImage provides a mock function for the type MockStorager
+func (_mock *MockStorager) AddAlbumImage(ctx context.Context, tenant string, albumID int, imageID int) error {
+	ret := _mock.Called(ctx, tenant, albumID, imageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAlbumImage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) error); ok {
+		r0 = returnFunc(ctx, tenant, albumID, imageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_AddAlbumImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAlbumImage'
+type MockStorager_AddAlbumImage_Call struct {
+	*mock.Call
+}
+
+// AddAlbumImage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - albumID int
+//   - imageID int
+func (_e *MockStorager_Expecter) AddAlbumImage(ctx interface{}, tenant interface{}, albumID interface{}, imageID interface{}) *MockStorager_AddAlbumImage_Call {
+	return &MockStorager_AddAlbumImage_Call{Call: _e.mock.On("AddAlbumImage", ctx, tenant, albumID, imageID)}
+}
+
+func (_c *MockStorager_AddAlbumImage_Call) Run(run func(ctx context.Context, tenant string, albumID int, imageID int)) *MockStorager_AddAlbumImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_AddAlbumImage_Call) Return(err error) *MockStorager_AddAlbumImage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_AddAlbumImage_Call) RunAndReturn(run func(ctx context.Context, tenant string, albumID int, imageID int) error) *MockStorager_AddAlbumImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Close provides a mock function for the type MockStorager
 func (_mock *MockStorager) Close() error {
 	ret := _mock.Called()
@@ -83,53 +152,53 @@ func (_c *MockStorager_Close_Call) RunAndReturn(run func() error) *MockStorager_
 	return _c
 }
 
-// CreateImage provides a mock function for the type MockStorager
-func (_mock *MockStorager) CreateImage(ctx context.Context, img model.ImageInCreate) (int, error) {
-	ret := _mock.Called(ctx, img)
+// CreateAPIKey provides a mock function for the type MockStorager
+func (_mock *MockStorager) CreateAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error) {
+	ret := _mock.Called(ctx, key)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateImage")
+		panic("no return value specified for CreateAPIKey")
 	}
 
-	var r0 int
+	var r0 model.APIKey
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.ImageInCreate) (int, error)); ok {
-		return returnFunc(ctx, img)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.APIKey) (model.APIKey, error)); ok {
+		return returnFunc(ctx, key)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.ImageInCreate) int); ok {
-		r0 = returnFunc(ctx, img)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.APIKey) model.APIKey); ok {
+		r0 = returnFunc(ctx, key)
 	} else {
-		r0 = ret.Get(0).(int)
+		r0 = ret.Get(0).(model.APIKey)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, model.ImageInCreate) error); ok {
-		r1 = returnFunc(ctx, img)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.APIKey) error); ok {
+		r1 = returnFunc(ctx, key)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockStorager_CreateImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateImage'
-type MockStorager_CreateImage_Call struct {
+// MockStorager_CreateAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAPIKey'
+type MockStorager_CreateAPIKey_Call struct {
 	*mock.Call
 }
 
-// CreateImage is a helper method to define mock.On call
+// CreateAPIKey is a helper method to define mock.On call
 //   - ctx context.Context
-//   - img model.ImageInCreate
-func (_e *MockStorager_Expecter) CreateImage(ctx interface{}, img interface{}) *MockStorager_CreateImage_Call {
-	return &MockStorager_CreateImage_Call{Call: _e.mock.On("CreateImage", ctx, img)}
+//   - key model.APIKey
+func (_e *MockStorager_Expecter) CreateAPIKey(ctx interface{}, key interface{}) *MockStorager_CreateAPIKey_Call {
+	return &MockStorager_CreateAPIKey_Call{Call: _e.mock.On("CreateAPIKey", ctx, key)}
 }
 
-func (_c *MockStorager_CreateImage_Call) Run(run func(ctx context.Context, img model.ImageInCreate)) *MockStorager_CreateImage_Call {
+func (_c *MockStorager_CreateAPIKey_Call) Run(run func(ctx context.Context, key model.APIKey)) *MockStorager_CreateAPIKey_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 model.ImageInCreate
+		var arg1 model.APIKey
 		if args[1] != nil {
-			arg1 = args[1].(model.ImageInCreate)
+			arg1 = args[1].(model.APIKey)
 		}
 		run(
 			arg0,
@@ -139,54 +208,63 @@ func (_c *MockStorager_CreateImage_Call) Run(run func(ctx context.Context, img m
 	return _c
 }
 
-func (_c *MockStorager_CreateImage_Call) Return(n int, err error) *MockStorager_CreateImage_Call {
-	_c.Call.Return(n, err)
+func (_c *MockStorager_CreateAPIKey_Call) Return(aPIKey model.APIKey, err error) *MockStorager_CreateAPIKey_Call {
+	_c.Call.Return(aPIKey, err)
 	return _c
 }
 
-func (_c *MockStorager_CreateImage_Call) RunAndReturn(run func(ctx context.Context, img model.ImageInCreate) (int, error)) *MockStorager_CreateImage_Call {
+func (_c *MockStorager_CreateAPIKey_Call) RunAndReturn(run func(ctx context.Context, key model.APIKey) (model.APIKey, error)) *MockStorager_CreateAPIKey_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteImage provides a mock function for the type MockStorager
-func (_mock *MockStorager) DeleteImage(ctx context.Context, id int) error {
-	ret := _mock.Called(ctx, id)
+// CreateAlbum provides a mock function for the type MockStorager
+func (_mock *MockStorager) CreateAlbum(ctx context.Context, album model.Album) (model.Album, error) {
+	ret := _mock.Called(ctx, album)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteImage")
+		panic("no return value specified for CreateAlbum")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = returnFunc(ctx, id)
+	var r0 model.Album
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Album) (model.Album, error)); ok {
+		return returnFunc(ctx, album)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Album) model.Album); ok {
+		r0 = returnFunc(ctx, album)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(model.Album)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.Album) error); ok {
+		r1 = returnFunc(ctx, album)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStorager_DeleteImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteImage'
-type MockStorager_DeleteImage_Call struct {
+// MockStorager_CreateAlbum_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAlbum'
+type MockStorager_CreateAlbum_Call struct {
 	*mock.Call
 }
 
-// DeleteImage is a helper method to define mock.On call
+// CreateAlbum is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id int
-func (_e *MockStorager_Expecter) DeleteImage(ctx interface{}, id interface{}) *MockStorager_DeleteImage_Call {
-	return &MockStorager_DeleteImage_Call{Call: _e.mock.On("DeleteImage", ctx, id)}
+//   - album model.Album
+func (_e *MockStorager_Expecter) CreateAlbum(ctx interface{}, album interface{}) *MockStorager_CreateAlbum_Call {
+	return &MockStorager_CreateAlbum_Call{Call: _e.mock.On("CreateAlbum", ctx, album)}
 }
 
-func (_c *MockStorager_DeleteImage_Call) Run(run func(ctx context.Context, id int)) *MockStorager_DeleteImage_Call {
+func (_c *MockStorager_CreateAlbum_Call) Run(run func(ctx context.Context, album model.Album)) *MockStorager_CreateAlbum_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 model.Album
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(model.Album)
 		}
 		run(
 			arg0,
@@ -196,123 +274,135 @@ func (_c *MockStorager_DeleteImage_Call) Run(run func(ctx context.Context, id in
 	return _c
 }
 
-func (_c *MockStorager_DeleteImage_Call) Return(err error) *MockStorager_DeleteImage_Call {
-	_c.Call.Return(err)
+func (_c *MockStorager_CreateAlbum_Call) Return(album1 model.Album, err error) *MockStorager_CreateAlbum_Call {
+	_c.Call.Return(album1, err)
 	return _c
 }
 
-func (_c *MockStorager_DeleteImage_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockStorager_DeleteImage_Call {
+func (_c *MockStorager_CreateAlbum_Call) RunAndReturn(run func(ctx context.Context, album model.Album) (model.Album, error)) *MockStorager_CreateAlbum_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCountImages provides a mock function for the type MockStorager
-func (_mock *MockStorager) GetCountImages(ctx context.Context) (int, error) {
-	ret := _mock.Called(ctx)
+// CreateDerivative provides a mock function for the type MockStorager
+func (_mock *MockStorager) CreateDerivative(ctx context.Context, tenant string, d model.Derivative) (int, error) {
+	ret := _mock.Called(ctx, tenant, d)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCountImages")
+		panic("no return value specified for CreateDerivative")
 	}
 
 	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.Derivative) (int, error)); ok {
+		return returnFunc(ctx, tenant, d)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.Derivative) int); ok {
+		r0 = returnFunc(ctx, tenant, d)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, model.Derivative) error); ok {
+		r1 = returnFunc(ctx, tenant, d)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockStorager_GetCountImages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCountImages'
-type MockStorager_GetCountImages_Call struct {
+// MockStorager_CreateDerivative_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDerivative'
+type MockStorager_CreateDerivative_Call struct {
 	*mock.Call
 }
 
-// GetCountImages is a helper method to define mock.On call
+// CreateDerivative is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockStorager_Expecter) GetCountImages(ctx interface{}) *MockStorager_GetCountImages_Call {
-	return &MockStorager_GetCountImages_Call{Call: _e.mock.On("GetCountImages", ctx)}
+//   - tenant string
+//   - d model.Derivative
+func (_e *MockStorager_Expecter) CreateDerivative(ctx interface{}, tenant interface{}, d interface{}) *MockStorager_CreateDerivative_Call {
+	return &MockStorager_CreateDerivative_Call{Call: _e.mock.On("CreateDerivative", ctx, tenant, d)}
 }
 
-func (_c *MockStorager_GetCountImages_Call) Run(run func(ctx context.Context)) *MockStorager_GetCountImages_Call {
+func (_c *MockStorager_CreateDerivative_Call) Run(run func(ctx context.Context, tenant string, d model.Derivative)) *MockStorager_CreateDerivative_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 model.Derivative
+		if args[2] != nil {
+			arg2 = args[2].(model.Derivative)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorager_GetCountImages_Call) Return(n int, err error) *MockStorager_GetCountImages_Call {
+func (_c *MockStorager_CreateDerivative_Call) Return(n int, err error) *MockStorager_CreateDerivative_Call {
 	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockStorager_GetCountImages_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockStorager_GetCountImages_Call {
+func (_c *MockStorager_CreateDerivative_Call) RunAndReturn(run func(ctx context.Context, tenant string, d model.Derivative) (int, error)) *MockStorager_CreateDerivative_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetImage provides a mock function for the type MockStorager
-func (_mock *MockStorager) GetImage(ctx context.Context, id int) (model.ImageInRepo, error) {
-	ret := _mock.Called(ctx, id)
+// CreateImage provides a mock function for the type MockStorager
+func (_mock *MockStorager) CreateImage(ctx context.Context, img model.ImageInCreate) (int, error) {
+	ret := _mock.Called(ctx, img)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetImage")
+		panic("no return value specified for CreateImage")
 	}
 
-	var r0 model.ImageInRepo
+	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (model.ImageInRepo, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.ImageInCreate) (int, error)); ok {
+		return returnFunc(ctx, img)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) model.ImageInRepo); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.ImageInCreate) int); ok {
+		r0 = returnFunc(ctx, img)
 	} else {
-		r0 = ret.Get(0).(model.ImageInRepo)
+		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.ImageInCreate) error); ok {
+		r1 = returnFunc(ctx, img)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockStorager_GetImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImage'
-type MockStorager_GetImage_Call struct {
+// MockStorager_CreateImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateImage'
+type MockStorager_CreateImage_Call struct {
 	*mock.Call
 }
 
-// GetImage is a helper method to define mock.On call
+// CreateImage is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id int
-func (_e *MockStorager_Expecter) GetImage(ctx interface{}, id interface{}) *MockStorager_GetImage_Call {
-	return &MockStorager_GetImage_Call{Call: _e.mock.On("GetImage", ctx, id)}
+//   - img model.ImageInCreate
+func (_e *MockStorager_Expecter) CreateImage(ctx interface{}, img interface{}) *MockStorager_CreateImage_Call {
+	return &MockStorager_CreateImage_Call{Call: _e.mock.On("CreateImage", ctx, img)}
 }
 
-func (_c *MockStorager_GetImage_Call) Run(run func(ctx context.Context, id int)) *MockStorager_GetImage_Call {
+func (_c *MockStorager_CreateImage_Call) Run(run func(ctx context.Context, img model.ImageInCreate)) *MockStorager_CreateImage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 model.ImageInCreate
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(model.ImageInCreate)
 		}
 		run(
 			arg0,
@@ -322,149 +412,1930 @@ func (_c *MockStorager_GetImage_Call) Run(run func(ctx context.Context, id int))
 	return _c
 }
 
-func (_c *MockStorager_GetImage_Call) Return(imageInRepo model.ImageInRepo, err error) *MockStorager_GetImage_Call {
-	_c.Call.Return(imageInRepo, err)
+func (_c *MockStorager_CreateImage_Call) Return(n int, err error) *MockStorager_CreateImage_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockStorager_GetImage_Call) RunAndReturn(run func(ctx context.Context, id int) (model.ImageInRepo, error)) *MockStorager_GetImage_Call {
+func (_c *MockStorager_CreateImage_Call) RunAndReturn(run func(ctx context.Context, img model.ImageInCreate) (int, error)) *MockStorager_CreateImage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetImages provides a mock function for the type MockStorager
-func (_mock *MockStorager) GetImages(ctx context.Context, lastCreatedAt time.Time, lastID int, mode string) ([]model.ImageInRepo, error) {
-	ret := _mock.Called(ctx, lastCreatedAt, lastID, mode)
+// CreateTransformation provides a mock function for the type MockStorager
+func (_mock *MockStorager) CreateTransformation(ctx context.Context, t model.Transformation) (model.Transformation, error) {
+	ret := _mock.Called(ctx, t)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetImages")
+		panic("no return value specified for CreateTransformation")
 	}
 
-	var r0 []model.ImageInRepo
+	var r0 model.Transformation
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int, string) ([]model.ImageInRepo, error)); ok {
-		return returnFunc(ctx, lastCreatedAt, lastID, mode)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Transformation) (model.Transformation, error)); ok {
+		return returnFunc(ctx, t)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int, string) []model.ImageInRepo); ok {
-		r0 = returnFunc(ctx, lastCreatedAt, lastID, mode)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Transformation) model.Transformation); ok {
+		r0 = returnFunc(ctx, t)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.ImageInRepo)
-		}
+		r0 = ret.Get(0).(model.Transformation)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int, string) error); ok {
-		r1 = returnFunc(ctx, lastCreatedAt, lastID, mode)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.Transformation) error); ok {
+		r1 = returnFunc(ctx, t)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockStorager_GetImages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImages'
-type MockStorager_GetImages_Call struct {
+// MockStorager_CreateTransformation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTransformation'
+type MockStorager_CreateTransformation_Call struct {
 	*mock.Call
 }
 
-// GetImages is a helper method to define mock.On call
+// CreateTransformation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - t model.Transformation
+func (_e *MockStorager_Expecter) CreateTransformation(ctx interface{}, t interface{}) *MockStorager_CreateTransformation_Call {
+	return &MockStorager_CreateTransformation_Call{Call: _e.mock.On("CreateTransformation", ctx, t)}
+}
+
+func (_c *MockStorager_CreateTransformation_Call) Run(run func(ctx context.Context, t model.Transformation)) *MockStorager_CreateTransformation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.Transformation
+		if args[1] != nil {
+			arg1 = args[1].(model.Transformation)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_CreateTransformation_Call) Return(transformation model.Transformation, err error) *MockStorager_CreateTransformation_Call {
+	_c.Call.Return(transformation, err)
+	return _c
+}
+
+func (_c *MockStorager_CreateTransformation_Call) RunAndReturn(run func(ctx context.Context, t model.Transformation) (model.Transformation, error)) *MockStorager_CreateTransformation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAlbum provides a mock function for the type MockStorager
+func (_mock *MockStorager) DeleteAlbum(ctx context.Context, tenant string, id int) error {
+	ret := _mock.Called(ctx, tenant, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAlbum")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = returnFunc(ctx, tenant, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_DeleteAlbum_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAlbum'
+type MockStorager_DeleteAlbum_Call struct {
+	*mock.Call
+}
+
+// DeleteAlbum is a helper method to define mock.On call
 //   - ctx context.Context
-//   - lastCreatedAt time.Time
-//   - lastID int
-//   - mode string
-func (_e *MockStorager_Expecter) GetImages(ctx interface{}, lastCreatedAt interface{}, lastID interface{}, mode interface{}) *MockStorager_GetImages_Call {
-	return &MockStorager_GetImages_Call{Call: _e.mock.On("GetImages", ctx, lastCreatedAt, lastID, mode)}
+//   - tenant string
+//   - id int
+func (_e *MockStorager_Expecter) DeleteAlbum(ctx interface{}, tenant interface{}, id interface{}) *MockStorager_DeleteAlbum_Call {
+	return &MockStorager_DeleteAlbum_Call{Call: _e.mock.On("DeleteAlbum", ctx, tenant, id)}
 }
 
-func (_c *MockStorager_GetImages_Call) Run(run func(ctx context.Context, lastCreatedAt time.Time, lastID int, mode string)) *MockStorager_GetImages_Call {
+func (_c *MockStorager_DeleteAlbum_Call) Run(run func(ctx context.Context, tenant string, id int)) *MockStorager_DeleteAlbum_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 time.Time
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(time.Time)
+			arg1 = args[1].(string)
 		}
 		var arg2 int
 		if args[2] != nil {
 			arg2 = args[2].(int)
 		}
-		var arg3 string
-		if args[3] != nil {
-			arg3 = args[3].(string)
-		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorager_GetImages_Call) Return(imageInRepos []model.ImageInRepo, err error) *MockStorager_GetImages_Call {
-	_c.Call.Return(imageInRepos, err)
+func (_c *MockStorager_DeleteAlbum_Call) Return(err error) *MockStorager_DeleteAlbum_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorager_GetImages_Call) RunAndReturn(run func(ctx context.Context, lastCreatedAt time.Time, lastID int, mode string) ([]model.ImageInRepo, error)) *MockStorager_GetImages_Call {
+func (_c *MockStorager_DeleteAlbum_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int) error) *MockStorager_DeleteAlbum_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateImage provides a mock function for the type MockStorager
-func (_mock *MockStorager) UpdateImage(ctx context.Context, img model.ImageInRepo) error {
-	ret := _mock.Called(ctx, img)
+// DeleteDerivative provides a mock function for the type MockStorager
+func (_mock *MockStorager) DeleteDerivative(ctx context.Context, tenant string, id int) error {
+	ret := _mock.Called(ctx, tenant, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateImage")
+		panic("no return value specified for DeleteDerivative")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.ImageInRepo) error); ok {
-		r0 = returnFunc(ctx, img)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = returnFunc(ctx, tenant, id)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorager_UpdateImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateImage'
-type MockStorager_UpdateImage_Call struct {
+// MockStorager_DeleteDerivative_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDerivative'
+type MockStorager_DeleteDerivative_Call struct {
 	*mock.Call
 }
 
-// UpdateImage is a helper method to define mock.On call
+// DeleteDerivative is a helper method to define mock.On call
 //   - ctx context.Context
-//   - img model.ImageInRepo
-func (_e *MockStorager_Expecter) UpdateImage(ctx interface{}, img interface{}) *MockStorager_UpdateImage_Call {
-	return &MockStorager_UpdateImage_Call{Call: _e.mock.On("UpdateImage", ctx, img)}
+//   - tenant string
+//   - id int
+func (_e *MockStorager_Expecter) DeleteDerivative(ctx interface{}, tenant interface{}, id interface{}) *MockStorager_DeleteDerivative_Call {
+	return &MockStorager_DeleteDerivative_Call{Call: _e.mock.On("DeleteDerivative", ctx, tenant, id)}
 }
 
-func (_c *MockStorager_UpdateImage_Call) Run(run func(ctx context.Context, img model.ImageInRepo)) *MockStorager_UpdateImage_Call {
+func (_c *MockStorager_DeleteDerivative_Call) Run(run func(ctx context.Context, tenant string, id int)) *MockStorager_DeleteDerivative_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 model.ImageInRepo
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(model.ImageInRepo)
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorager_UpdateImage_Call) Return(err error) *MockStorager_UpdateImage_Call {
+func (_c *MockStorager_DeleteDerivative_Call) Return(err error) *MockStorager_DeleteDerivative_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_DeleteDerivative_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int) error) *MockStorager_DeleteDerivative_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteImage provides a mock function for the type MockStorager
+func (_mock *MockStorager) DeleteImage(ctx context.Context, tenant string, id int) error {
+	ret := _mock.Called(ctx, tenant, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteImage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = returnFunc(ctx, tenant, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_DeleteImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteImage'
+type MockStorager_DeleteImage_Call struct {
+	*mock.Call
+}
+
+// DeleteImage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - id int
+func (_e *MockStorager_Expecter) DeleteImage(ctx interface{}, tenant interface{}, id interface{}) *MockStorager_DeleteImage_Call {
+	return &MockStorager_DeleteImage_Call{Call: _e.mock.On("DeleteImage", ctx, tenant, id)}
+}
+
+func (_c *MockStorager_DeleteImage_Call) Run(run func(ctx context.Context, tenant string, id int)) *MockStorager_DeleteImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_DeleteImage_Call) Return(err error) *MockStorager_DeleteImage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_DeleteImage_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int) error) *MockStorager_DeleteImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTransformation provides a mock function for the type MockStorager
+func (_mock *MockStorager) DeleteTransformation(ctx context.Context, tenant string, name string) error {
+	ret := _mock.Called(ctx, tenant, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTransformation")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, tenant, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_DeleteTransformation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTransformation'
+type MockStorager_DeleteTransformation_Call struct {
+	*mock.Call
+}
+
+// DeleteTransformation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - name string
+func (_e *MockStorager_Expecter) DeleteTransformation(ctx interface{}, tenant interface{}, name interface{}) *MockStorager_DeleteTransformation_Call {
+	return &MockStorager_DeleteTransformation_Call{Call: _e.mock.On("DeleteTransformation", ctx, tenant, name)}
+}
+
+func (_c *MockStorager_DeleteTransformation_Call) Run(run func(ctx context.Context, tenant string, name string)) *MockStorager_DeleteTransformation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_DeleteTransformation_Call) Return(err error) *MockStorager_DeleteTransformation_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_DeleteTransformation_Call) RunAndReturn(run func(ctx context.Context, tenant string, name string) error) *MockStorager_DeleteTransformation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAPIKey provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetAPIKey(ctx context.Context, tenant string, id int) (model.APIKey, error) {
+	ret := _mock.Called(ctx, tenant, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKey")
+	}
+
+	var r0 model.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) (model.APIKey, error)); ok {
+		return returnFunc(ctx, tenant, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) model.APIKey); ok {
+		r0 = returnFunc(ctx, tenant, id)
+	} else {
+		r0 = ret.Get(0).(model.APIKey)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, tenant, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAPIKey'
+type MockStorager_GetAPIKey_Call struct {
+	*mock.Call
+}
+
+// GetAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - id int
+func (_e *MockStorager_Expecter) GetAPIKey(ctx interface{}, tenant interface{}, id interface{}) *MockStorager_GetAPIKey_Call {
+	return &MockStorager_GetAPIKey_Call{Call: _e.mock.On("GetAPIKey", ctx, tenant, id)}
+}
+
+func (_c *MockStorager_GetAPIKey_Call) Run(run func(ctx context.Context, tenant string, id int)) *MockStorager_GetAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetAPIKey_Call) Return(aPIKey model.APIKey, err error) *MockStorager_GetAPIKey_Call {
+	_c.Call.Return(aPIKey, err)
+	return _c
+}
+
+func (_c *MockStorager_GetAPIKey_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int) (model.APIKey, error)) *MockStorager_GetAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAPIKeyByPrefix provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	ret := _mock.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeyByPrefix")
+	}
+
+	var r0 model.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (model.APIKey, error)); ok {
+		return returnFunc(ctx, prefix)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) model.APIKey); ok {
+		r0 = returnFunc(ctx, prefix)
+	} else {
+		r0 = ret.Get(0).(model.APIKey)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetAPIKeyByPrefix_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAPIKeyByPrefix'
+type MockStorager_GetAPIKeyByPrefix_Call struct {
+	*mock.Call
+}
+
+// GetAPIKeyByPrefix is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+func (_e *MockStorager_Expecter) GetAPIKeyByPrefix(ctx interface{}, prefix interface{}) *MockStorager_GetAPIKeyByPrefix_Call {
+	return &MockStorager_GetAPIKeyByPrefix_Call{Call: _e.mock.On("GetAPIKeyByPrefix", ctx, prefix)}
+}
+
+func (_c *MockStorager_GetAPIKeyByPrefix_Call) Run(run func(ctx context.Context, prefix string)) *MockStorager_GetAPIKeyByPrefix_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetAPIKeyByPrefix_Call) Return(aPIKey model.APIKey, err error) *MockStorager_GetAPIKeyByPrefix_Call {
+	_c.Call.Return(aPIKey, err)
+	return _c
+}
+
+func (_c *MockStorager_GetAPIKeyByPrefix_Call) RunAndReturn(run func(ctx context.Context, prefix string) (model.APIKey, error)) *MockStorager_GetAPIKeyByPrefix_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAPIKeys provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetAPIKeys(ctx context.Context, tenant string, userID string) ([]model.APIKey, error) {
+	ret := _mock.Called(ctx, tenant, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeys")
+	}
+
+	var r0 []model.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) ([]model.APIKey, error)); ok {
+		return returnFunc(ctx, tenant, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) []model.APIKey); ok {
+		r0 = returnFunc(ctx, tenant, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, tenant, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAPIKeys'
+type MockStorager_GetAPIKeys_Call struct {
+	*mock.Call
+}
+
+// GetAPIKeys is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - userID string
+func (_e *MockStorager_Expecter) GetAPIKeys(ctx interface{}, tenant interface{}, userID interface{}) *MockStorager_GetAPIKeys_Call {
+	return &MockStorager_GetAPIKeys_Call{Call: _e.mock.On("GetAPIKeys", ctx, tenant, userID)}
+}
+
+func (_c *MockStorager_GetAPIKeys_Call) Run(run func(ctx context.Context, tenant string, userID string)) *MockStorager_GetAPIKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetAPIKeys_Call) Return(aPIKeys []model.APIKey, err error) *MockStorager_GetAPIKeys_Call {
+	_c.Call.Return(aPIKeys, err)
+	return _c
+}
+
+func (_c *MockStorager_GetAPIKeys_Call) RunAndReturn(run func(ctx context.Context, tenant string, userID string) ([]model.APIKey, error)) *MockStorager_GetAPIKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAlbum provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetAlbum(ctx context.Context, tenant string, id int) (model.Album, error) {
+	ret := _mock.Called(ctx, tenant, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlbum")
+	}
+
+	var r0 model.Album
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) (model.Album, error)); ok {
+		return returnFunc(ctx, tenant, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) model.Album); ok {
+		r0 = returnFunc(ctx, tenant, id)
+	} else {
+		r0 = ret.Get(0).(model.Album)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, tenant, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetAlbum_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAlbum'
+type MockStorager_GetAlbum_Call struct {
+	*mock.Call
+}
+
+// GetAlbum is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - id int
+func (_e *MockStorager_Expecter) GetAlbum(ctx interface{}, tenant interface{}, id interface{}) *MockStorager_GetAlbum_Call {
+	return &MockStorager_GetAlbum_Call{Call: _e.mock.On("GetAlbum", ctx, tenant, id)}
+}
+
+func (_c *MockStorager_GetAlbum_Call) Run(run func(ctx context.Context, tenant string, id int)) *MockStorager_GetAlbum_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetAlbum_Call) Return(album model.Album, err error) *MockStorager_GetAlbum_Call {
+	_c.Call.Return(album, err)
+	return _c
+}
+
+func (_c *MockStorager_GetAlbum_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int) (model.Album, error)) *MockStorager_GetAlbum_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAlbums provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetAlbums(ctx context.Context, tenant string, owner string) ([]model.Album, error) {
+	ret := _mock.Called(ctx, tenant, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlbums")
+	}
+
+	var r0 []model.Album
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) ([]model.Album, error)); ok {
+		return returnFunc(ctx, tenant, owner)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) []model.Album); ok {
+		r0 = returnFunc(ctx, tenant, owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Album)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, tenant, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetAlbums_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAlbums'
+type MockStorager_GetAlbums_Call struct {
+	*mock.Call
+}
+
+// GetAlbums is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - owner string
+func (_e *MockStorager_Expecter) GetAlbums(ctx interface{}, tenant interface{}, owner interface{}) *MockStorager_GetAlbums_Call {
+	return &MockStorager_GetAlbums_Call{Call: _e.mock.On("GetAlbums", ctx, tenant, owner)}
+}
+
+func (_c *MockStorager_GetAlbums_Call) Run(run func(ctx context.Context, tenant string, owner string)) *MockStorager_GetAlbums_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetAlbums_Call) Return(albums []model.Album, err error) *MockStorager_GetAlbums_Call {
+	_c.Call.Return(albums, err)
+	return _c
+}
+
+func (_c *MockStorager_GetAlbums_Call) RunAndReturn(run func(ctx context.Context, tenant string, owner string) ([]model.Album, error)) *MockStorager_GetAlbums_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCountImages provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetCountImages(ctx context.Context, tenant string, filter model.ImageFilter) (int, error) {
+	ret := _mock.Called(ctx, tenant, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCountImages")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.ImageFilter) (int, error)); ok {
+		return returnFunc(ctx, tenant, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.ImageFilter) int); ok {
+		r0 = returnFunc(ctx, tenant, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, model.ImageFilter) error); ok {
+		r1 = returnFunc(ctx, tenant, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetCountImages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCountImages'
+type MockStorager_GetCountImages_Call struct {
+	*mock.Call
+}
+
+// GetCountImages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - filter model.ImageFilter
+func (_e *MockStorager_Expecter) GetCountImages(ctx interface{}, tenant interface{}, filter interface{}) *MockStorager_GetCountImages_Call {
+	return &MockStorager_GetCountImages_Call{Call: _e.mock.On("GetCountImages", ctx, tenant, filter)}
+}
+
+func (_c *MockStorager_GetCountImages_Call) Run(run func(ctx context.Context, tenant string, filter model.ImageFilter)) *MockStorager_GetCountImages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 model.ImageFilter
+		if args[2] != nil {
+			arg2 = args[2].(model.ImageFilter)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetCountImages_Call) Return(n int, err error) *MockStorager_GetCountImages_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockStorager_GetCountImages_Call) RunAndReturn(run func(ctx context.Context, tenant string, filter model.ImageFilter) (int, error)) *MockStorager_GetCountImages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDerivatives provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetDerivatives(ctx context.Context, tenant string, imageID int) ([]model.Derivative, error) {
+	ret := _mock.Called(ctx, tenant, imageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDerivatives")
+	}
+
+	var r0 []model.Derivative
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) ([]model.Derivative, error)); ok {
+		return returnFunc(ctx, tenant, imageID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) []model.Derivative); ok {
+		r0 = returnFunc(ctx, tenant, imageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Derivative)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, tenant, imageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetDerivatives_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDerivatives'
+type MockStorager_GetDerivatives_Call struct {
+	*mock.Call
+}
+
+// GetDerivatives is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - imageID int
+func (_e *MockStorager_Expecter) GetDerivatives(ctx interface{}, tenant interface{}, imageID interface{}) *MockStorager_GetDerivatives_Call {
+	return &MockStorager_GetDerivatives_Call{Call: _e.mock.On("GetDerivatives", ctx, tenant, imageID)}
+}
+
+func (_c *MockStorager_GetDerivatives_Call) Run(run func(ctx context.Context, tenant string, imageID int)) *MockStorager_GetDerivatives_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetDerivatives_Call) Return(derivatives []model.Derivative, err error) *MockStorager_GetDerivatives_Call {
+	_c.Call.Return(derivatives, err)
+	return _c
+}
+
+func (_c *MockStorager_GetDerivatives_Call) RunAndReturn(run func(ctx context.Context, tenant string, imageID int) ([]model.Derivative, error)) *MockStorager_GetDerivatives_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImage provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetImage(ctx context.Context, tenant string, id int) (model.ImageInRepo, error) {
+	ret := _mock.Called(ctx, tenant, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImage")
+	}
+
+	var r0 model.ImageInRepo
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) (model.ImageInRepo, error)); ok {
+		return returnFunc(ctx, tenant, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) model.ImageInRepo); ok {
+		r0 = returnFunc(ctx, tenant, id)
+	} else {
+		r0 = ret.Get(0).(model.ImageInRepo)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, tenant, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImage'
+type MockStorager_GetImage_Call struct {
+	*mock.Call
+}
+
+// GetImage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - id int
+func (_e *MockStorager_Expecter) GetImage(ctx interface{}, tenant interface{}, id interface{}) *MockStorager_GetImage_Call {
+	return &MockStorager_GetImage_Call{Call: _e.mock.On("GetImage", ctx, tenant, id)}
+}
+
+func (_c *MockStorager_GetImage_Call) Run(run func(ctx context.Context, tenant string, id int)) *MockStorager_GetImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetImage_Call) Return(imageInRepo model.ImageInRepo, err error) *MockStorager_GetImage_Call {
+	_c.Call.Return(imageInRepo, err)
+	return _c
+}
+
+func (_c *MockStorager_GetImage_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int) (model.ImageInRepo, error)) *MockStorager_GetImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImages provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetImages(ctx context.Context, tenant string, filter model.ImageFilter) ([]model.ImageInRepo, error) {
+	ret := _mock.Called(ctx, tenant, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImages")
+	}
+
+	var r0 []model.ImageInRepo
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.ImageFilter) ([]model.ImageInRepo, error)); ok {
+		return returnFunc(ctx, tenant, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.ImageFilter) []model.ImageInRepo); ok {
+		r0 = returnFunc(ctx, tenant, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ImageInRepo)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, model.ImageFilter) error); ok {
+		r1 = returnFunc(ctx, tenant, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetImages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImages'
+type MockStorager_GetImages_Call struct {
+	*mock.Call
+}
+
+// GetImages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - filter model.ImageFilter
+func (_e *MockStorager_Expecter) GetImages(ctx interface{}, tenant interface{}, filter interface{}) *MockStorager_GetImages_Call {
+	return &MockStorager_GetImages_Call{Call: _e.mock.On("GetImages", ctx, tenant, filter)}
+}
+
+func (_c *MockStorager_GetImages_Call) Run(run func(ctx context.Context, tenant string, filter model.ImageFilter)) *MockStorager_GetImages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 model.ImageFilter
+		if args[2] != nil {
+			arg2 = args[2].(model.ImageFilter)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetImages_Call) Return(imageInRepos []model.ImageInRepo, err error) *MockStorager_GetImages_Call {
+	_c.Call.Return(imageInRepos, err)
+	return _c
+}
+
+func (_c *MockStorager_GetImages_Call) RunAndReturn(run func(ctx context.Context, tenant string, filter model.ImageFilter) ([]model.ImageInRepo, error)) *MockStorager_GetImages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransformation provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetTransformation(ctx context.Context, tenant string, name string) (model.Transformation, error) {
+	ret := _mock.Called(ctx, tenant, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransformation")
+	}
+
+	var r0 model.Transformation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (model.Transformation, error)); ok {
+		return returnFunc(ctx, tenant, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) model.Transformation); ok {
+		r0 = returnFunc(ctx, tenant, name)
+	} else {
+		r0 = ret.Get(0).(model.Transformation)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, tenant, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetTransformation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransformation'
+type MockStorager_GetTransformation_Call struct {
+	*mock.Call
+}
+
+// GetTransformation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - name string
+func (_e *MockStorager_Expecter) GetTransformation(ctx interface{}, tenant interface{}, name interface{}) *MockStorager_GetTransformation_Call {
+	return &MockStorager_GetTransformation_Call{Call: _e.mock.On("GetTransformation", ctx, tenant, name)}
+}
+
+func (_c *MockStorager_GetTransformation_Call) Run(run func(ctx context.Context, tenant string, name string)) *MockStorager_GetTransformation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetTransformation_Call) Return(transformation model.Transformation, err error) *MockStorager_GetTransformation_Call {
+	_c.Call.Return(transformation, err)
+	return _c
+}
+
+func (_c *MockStorager_GetTransformation_Call) RunAndReturn(run func(ctx context.Context, tenant string, name string) (model.Transformation, error)) *MockStorager_GetTransformation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransformations provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetTransformations(ctx context.Context, tenant string) ([]model.Transformation, error) {
+	ret := _mock.Called(ctx, tenant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransformations")
+	}
+
+	var r0 []model.Transformation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]model.Transformation, error)); ok {
+		return returnFunc(ctx, tenant)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []model.Transformation); ok {
+		r0 = returnFunc(ctx, tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Transformation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, tenant)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetTransformations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransformations'
+type MockStorager_GetTransformations_Call struct {
+	*mock.Call
+}
+
+// GetTransformations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+func (_e *MockStorager_Expecter) GetTransformations(ctx interface{}, tenant interface{}) *MockStorager_GetTransformations_Call {
+	return &MockStorager_GetTransformations_Call{Call: _e.mock.On("GetTransformations", ctx, tenant)}
+}
+
+func (_c *MockStorager_GetTransformations_Call) Run(run func(ctx context.Context, tenant string)) *MockStorager_GetTransformations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetTransformations_Call) Return(transformations []model.Transformation, err error) *MockStorager_GetTransformations_Call {
+	_c.Call.Return(transformations, err)
+	return _c
+}
+
+func (_c *MockStorager_GetTransformations_Call) RunAndReturn(run func(ctx context.Context, tenant string) ([]model.Transformation, error)) *MockStorager_GetTransformations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUsage provides a mock function for the type MockStorager
+func (_mock *MockStorager) GetUsage(ctx context.Context, tenant string, owner string) (model.Usage, error) {
+	ret := _mock.Called(ctx, tenant, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsage")
+	}
+
+	var r0 model.Usage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (model.Usage, error)); ok {
+		return returnFunc(ctx, tenant, owner)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) model.Usage); ok {
+		r0 = returnFunc(ctx, tenant, owner)
+	} else {
+		r0 = ret.Get(0).(model.Usage)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, tenant, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_GetUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUsage'
+type MockStorager_GetUsage_Call struct {
+	*mock.Call
+}
+
+// GetUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - owner string
+func (_e *MockStorager_Expecter) GetUsage(ctx interface{}, tenant interface{}, owner interface{}) *MockStorager_GetUsage_Call {
+	return &MockStorager_GetUsage_Call{Call: _e.mock.On("GetUsage", ctx, tenant, owner)}
+}
+
+func (_c *MockStorager_GetUsage_Call) Run(run func(ctx context.Context, tenant string, owner string)) *MockStorager_GetUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_GetUsage_Call) Return(usage model.Usage, err error) *MockStorager_GetUsage_Call {
+	_c.Call.Return(usage, err)
+	return _c
+}
+
+func (_c *MockStorager_GetUsage_Call) RunAndReturn(run func(ctx context.Context, tenant string, owner string) (model.Usage, error)) *MockStorager_GetUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveAlbumImage provides a mock function for the type MockStorager
+func (_mock *MockStorager) RemoveAlbumImage(ctx context.Context, tenant string, albumID int, imageID int) error {
+	ret := _mock.Called(ctx, tenant, albumID, imageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveAlbumImage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) error); ok {
+		r0 = returnFunc(ctx, tenant, albumID, imageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_RemoveAlbumImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveAlbumImage'
+type MockStorager_RemoveAlbumImage_Call struct {
+	*mock.Call
+}
+
+// RemoveAlbumImage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - albumID int
+//   - imageID int
+func (_e *MockStorager_Expecter) RemoveAlbumImage(ctx interface{}, tenant interface{}, albumID interface{}, imageID interface{}) *MockStorager_RemoveAlbumImage_Call {
+	return &MockStorager_RemoveAlbumImage_Call{Call: _e.mock.On("RemoveAlbumImage", ctx, tenant, albumID, imageID)}
+}
+
+func (_c *MockStorager_RemoveAlbumImage_Call) Run(run func(ctx context.Context, tenant string, albumID int, imageID int)) *MockStorager_RemoveAlbumImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_RemoveAlbumImage_Call) Return(err error) *MockStorager_RemoveAlbumImage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_RemoveAlbumImage_Call) RunAndReturn(run func(ctx context.Context, tenant string, albumID int, imageID int) error) *MockStorager_RemoveAlbumImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAPIKey provides a mock function for the type MockStorager
+func (_mock *MockStorager) RevokeAPIKey(ctx context.Context, tenant string, id int) error {
+	ret := _mock.Called(ctx, tenant, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = returnFunc(ctx, tenant, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_RevokeAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAPIKey'
+type MockStorager_RevokeAPIKey_Call struct {
+	*mock.Call
+}
+
+// RevokeAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - id int
+func (_e *MockStorager_Expecter) RevokeAPIKey(ctx interface{}, tenant interface{}, id interface{}) *MockStorager_RevokeAPIKey_Call {
+	return &MockStorager_RevokeAPIKey_Call{Call: _e.mock.On("RevokeAPIKey", ctx, tenant, id)}
+}
+
+func (_c *MockStorager_RevokeAPIKey_Call) Run(run func(ctx context.Context, tenant string, id int)) *MockStorager_RevokeAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_RevokeAPIKey_Call) Return(err error) *MockStorager_RevokeAPIKey_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_RevokeAPIKey_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int) error) *MockStorager_RevokeAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RotateAPIKey provides a mock function for the type MockStorager
+func (_mock *MockStorager) RotateAPIKey(ctx context.Context, tenant string, id int, prefix string, keyHash string) error {
+	ret := _mock.Called(ctx, tenant, id, prefix, keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateAPIKey")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, string, string) error); ok {
+		r0 = returnFunc(ctx, tenant, id, prefix, keyHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_RotateAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateAPIKey'
+type MockStorager_RotateAPIKey_Call struct {
+	*mock.Call
+}
+
+// RotateAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - id int
+//   - prefix string
+//   - keyHash string
+func (_e *MockStorager_Expecter) RotateAPIKey(ctx interface{}, tenant interface{}, id interface{}, prefix interface{}, keyHash interface{}) *MockStorager_RotateAPIKey_Call {
+	return &MockStorager_RotateAPIKey_Call{Call: _e.mock.On("RotateAPIKey", ctx, tenant, id, prefix, keyHash)}
+}
+
+func (_c *MockStorager_RotateAPIKey_Call) Run(run func(ctx context.Context, tenant string, id int, prefix string, keyHash string)) *MockStorager_RotateAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 string
+		if args[4] != nil {
+			arg4 = args[4].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_RotateAPIKey_Call) Return(err error) *MockStorager_RotateAPIKey_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_RotateAPIKey_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int, prefix string, keyHash string) error) *MockStorager_RotateAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetImageSize provides a mock function for the type MockStorager
+func (_mock *MockStorager) SetImageSize(ctx context.Context, tenant string, id int, size int64) error {
+	ret := _mock.Called(ctx, tenant, id, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetImageSize")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int64) error); ok {
+		r0 = returnFunc(ctx, tenant, id, size)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_SetImageSize_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetImageSize'
+type MockStorager_SetImageSize_Call struct {
+	*mock.Call
+}
+
+// SetImageSize is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - id int
+//   - size int64
+func (_e *MockStorager_Expecter) SetImageSize(ctx interface{}, tenant interface{}, id interface{}, size interface{}) *MockStorager_SetImageSize_Call {
+	return &MockStorager_SetImageSize_Call{Call: _e.mock.On("SetImageSize", ctx, tenant, id, size)}
+}
+
+func (_c *MockStorager_SetImageSize_Call) Run(run func(ctx context.Context, tenant string, id int, size int64)) *MockStorager_SetImageSize_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_SetImageSize_Call) Return(err error) *MockStorager_SetImageSize_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_SetImageSize_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int, size int64) error) *MockStorager_SetImageSize_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TagImage provides a mock function for the type MockStorager
+func (_mock *MockStorager) TagImage(ctx context.Context, tenant string, imageID int, tag string) error {
+	ret := _mock.Called(ctx, tenant, imageID, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TagImage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, string) error); ok {
+		r0 = returnFunc(ctx, tenant, imageID, tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_TagImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TagImage'
+type MockStorager_TagImage_Call struct {
+	*mock.Call
+}
+
+// TagImage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - imageID int
+//   - tag string
+func (_e *MockStorager_Expecter) TagImage(ctx interface{}, tenant interface{}, imageID interface{}, tag interface{}) *MockStorager_TagImage_Call {
+	return &MockStorager_TagImage_Call{Call: _e.mock.On("TagImage", ctx, tenant, imageID, tag)}
+}
+
+func (_c *MockStorager_TagImage_Call) Run(run func(ctx context.Context, tenant string, imageID int, tag string)) *MockStorager_TagImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_TagImage_Call) Return(err error) *MockStorager_TagImage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_TagImage_Call) RunAndReturn(run func(ctx context.Context, tenant string, imageID int, tag string) error) *MockStorager_TagImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TouchAPIKey provides a mock function for the type MockStorager
+func (_mock *MockStorager) TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error {
+	ret := _mock.Called(ctx, id, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchAPIKey")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_TouchAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchAPIKey'
+type MockStorager_TouchAPIKey_Call struct {
+	*mock.Call
+}
+
+// TouchAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - usedAt time.Time
+func (_e *MockStorager_Expecter) TouchAPIKey(ctx interface{}, id interface{}, usedAt interface{}) *MockStorager_TouchAPIKey_Call {
+	return &MockStorager_TouchAPIKey_Call{Call: _e.mock.On("TouchAPIKey", ctx, id, usedAt)}
+}
+
+func (_c *MockStorager_TouchAPIKey_Call) Run(run func(ctx context.Context, id int, usedAt time.Time)) *MockStorager_TouchAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_TouchAPIKey_Call) Return(err error) *MockStorager_TouchAPIKey_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_TouchAPIKey_Call) RunAndReturn(run func(ctx context.Context, id int, usedAt time.Time) error) *MockStorager_TouchAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UntagImage provides a mock function for the type MockStorager
+func (_mock *MockStorager) UntagImage(ctx context.Context, tenant string, imageID int, tag string) error {
+	ret := _mock.Called(ctx, tenant, imageID, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UntagImage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, string) error); ok {
+		r0 = returnFunc(ctx, tenant, imageID, tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_UntagImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UntagImage'
+type MockStorager_UntagImage_Call struct {
+	*mock.Call
+}
+
+// UntagImage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - imageID int
+//   - tag string
+func (_e *MockStorager_Expecter) UntagImage(ctx interface{}, tenant interface{}, imageID interface{}, tag interface{}) *MockStorager_UntagImage_Call {
+	return &MockStorager_UntagImage_Call{Call: _e.mock.On("UntagImage", ctx, tenant, imageID, tag)}
+}
+
+func (_c *MockStorager_UntagImage_Call) Run(run func(ctx context.Context, tenant string, imageID int, tag string)) *MockStorager_UntagImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_UntagImage_Call) Return(err error) *MockStorager_UntagImage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_UntagImage_Call) RunAndReturn(run func(ctx context.Context, tenant string, imageID int, tag string) error) *MockStorager_UntagImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateDerivative provides a mock function for the type MockStorager
+func (_mock *MockStorager) UpdateDerivative(ctx context.Context, tenant string, d model.Derivative) error {
+	ret := _mock.Called(ctx, tenant, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDerivative")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.Derivative) error); ok {
+		r0 = returnFunc(ctx, tenant, d)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_UpdateDerivative_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDerivative'
+type MockStorager_UpdateDerivative_Call struct {
+	*mock.Call
+}
+
+// UpdateDerivative is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - d model.Derivative
+func (_e *MockStorager_Expecter) UpdateDerivative(ctx interface{}, tenant interface{}, d interface{}) *MockStorager_UpdateDerivative_Call {
+	return &MockStorager_UpdateDerivative_Call{Call: _e.mock.On("UpdateDerivative", ctx, tenant, d)}
+}
+
+func (_c *MockStorager_UpdateDerivative_Call) Run(run func(ctx context.Context, tenant string, d model.Derivative)) *MockStorager_UpdateDerivative_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 model.Derivative
+		if args[2] != nil {
+			arg2 = args[2].(model.Derivative)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_UpdateDerivative_Call) Return(err error) *MockStorager_UpdateDerivative_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_UpdateDerivative_Call) RunAndReturn(run func(ctx context.Context, tenant string, d model.Derivative) error) *MockStorager_UpdateDerivative_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateImage provides a mock function for the type MockStorager
+func (_mock *MockStorager) UpdateImage(ctx context.Context, tenant string, img model.ImageInRepo) error {
+	ret := _mock.Called(ctx, tenant, img)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateImage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, model.ImageInRepo) error); ok {
+		r0 = returnFunc(ctx, tenant, img)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_UpdateImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateImage'
+type MockStorager_UpdateImage_Call struct {
+	*mock.Call
+}
+
+// UpdateImage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - img model.ImageInRepo
+func (_e *MockStorager_Expecter) UpdateImage(ctx interface{}, tenant interface{}, img interface{}) *MockStorager_UpdateImage_Call {
+	return &MockStorager_UpdateImage_Call{Call: _e.mock.On("UpdateImage", ctx, tenant, img)}
+}
+
+func (_c *MockStorager_UpdateImage_Call) Run(run func(ctx context.Context, tenant string, img model.ImageInRepo)) *MockStorager_UpdateImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 model.ImageInRepo
+		if args[2] != nil {
+			arg2 = args[2].(model.ImageInRepo)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_UpdateImage_Call) Return(err error) *MockStorager_UpdateImage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorager_UpdateImage_Call) RunAndReturn(run func(ctx context.Context, tenant string, img model.ImageInRepo) error) *MockStorager_UpdateImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTransformation provides a mock function for the type MockStorager
+func (_mock *MockStorager) UpdateTransformation(ctx context.Context, t model.Transformation) (model.Transformation, error) {
+	ret := _mock.Called(ctx, t)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTransformation")
+	}
+
+	var r0 model.Transformation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Transformation) (model.Transformation, error)); ok {
+		return returnFunc(ctx, t)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Transformation) model.Transformation); ok {
+		r0 = returnFunc(ctx, t)
+	} else {
+		r0 = ret.Get(0).(model.Transformation)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.Transformation) error); ok {
+		r1 = returnFunc(ctx, t)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorager_UpdateTransformation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTransformation'
+type MockStorager_UpdateTransformation_Call struct {
+	*mock.Call
+}
+
+// UpdateTransformation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - t model.Transformation
+func (_e *MockStorager_Expecter) UpdateTransformation(ctx interface{}, t interface{}) *MockStorager_UpdateTransformation_Call {
+	return &MockStorager_UpdateTransformation_Call{Call: _e.mock.On("UpdateTransformation", ctx, t)}
+}
+
+func (_c *MockStorager_UpdateTransformation_Call) Run(run func(ctx context.Context, t model.Transformation)) *MockStorager_UpdateTransformation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.Transformation
+		if args[1] != nil {
+			arg1 = args[1].(model.Transformation)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_UpdateTransformation_Call) Return(transformation model.Transformation, err error) *MockStorager_UpdateTransformation_Call {
+	_c.Call.Return(transformation, err)
+	return _c
+}
+
+func (_c *MockStorager_UpdateTransformation_Call) RunAndReturn(run func(ctx context.Context, t model.Transformation) (model.Transformation, error)) *MockStorager_UpdateTransformation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateVisibility provides a mock function for the type MockStorager
+func (_mock *MockStorager) UpdateVisibility(ctx context.Context, tenant string, id int, visibility string) error {
+	ret := _mock.Called(ctx, tenant, id, visibility)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateVisibility")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, string) error); ok {
+		r0 = returnFunc(ctx, tenant, id, visibility)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorager_UpdateVisibility_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateVisibility'
+type MockStorager_UpdateVisibility_Call struct {
+	*mock.Call
+}
+
+// UpdateVisibility is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tenant string
+//   - id int
+//   - visibility string
+func (_e *MockStorager_Expecter) UpdateVisibility(ctx interface{}, tenant interface{}, id interface{}, visibility interface{}) *MockStorager_UpdateVisibility_Call {
+	return &MockStorager_UpdateVisibility_Call{Call: _e.mock.On("UpdateVisibility", ctx, tenant, id, visibility)}
+}
+
+func (_c *MockStorager_UpdateVisibility_Call) Run(run func(ctx context.Context, tenant string, id int, visibility string)) *MockStorager_UpdateVisibility_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorager_UpdateVisibility_Call) Return(err error) *MockStorager_UpdateVisibility_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorager_UpdateImage_Call) RunAndReturn(run func(ctx context.Context, img model.ImageInRepo) error) *MockStorager_UpdateImage_Call {
+func (_c *MockStorager_UpdateVisibility_Call) RunAndReturn(run func(ctx context.Context, tenant string, id int, visibility string) error) *MockStorager_UpdateVisibility_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,85 @@
+package repositorytest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/repository"
+)
+
+// fakeS3 accepts single and multipart uploads and records the size of every
+// uploaded part.
+type fakeS3 struct {
+	mu    sync.Mutex
+	parts []int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	size := r.ContentLength
+	if decoded := r.Header.Get("X-Amz-Decoded-Content-Length"); decoded != "" {
+		size, _ = strconv.ParseInt(decoded, 10, 64)
+	}
+	_, _ = io.Copy(io.Discard, r.Body)
+
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><Bucket>images</Bucket><Key>uploads/big.png</Key><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><Bucket>images</Bucket><Key>uploads/big.png</Key><ETag>"done"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodPut:
+		f.mu.Lock()
+		f.parts = append(f.parts, int(size))
+		f.mu.Unlock()
+		w.Header().Set("ETag", `"part"`)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func newFakeStorage(t *testing.T, handler http.Handler) *repository.ImageStorage {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4("user", "password", ""),
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	return &repository.ImageStorage{Client: client, BucketName: "images"}
+}
+
+func TestUploadUnknownSize(t *testing.T) {
+	s3 := &fakeS3{}
+	storage := newFakeStorage(t, s3)
+
+	// MultiReader hides the size of the bytes.Reader from minio-go.
+	data := bytes.Repeat([]byte{1}, repository.StreamPartSize+1<<20)
+	err := storage.Upload(context.Background(), io.MultiReader(bytes.NewReader(data)), "uploads/big.png", -1)
+	require.NoError(t, err)
+	require.Equal(t, []int{repository.StreamPartSize, 1 << 20}, s3.parts)
+}
+
+func TestUploadSmallUnknownSize(t *testing.T) {
+	s3 := &fakeS3{}
+	storage := newFakeStorage(t, s3)
+
+	err := storage.Upload(context.Background(), io.MultiReader(bytes.NewReader([]byte("png"))), "uploads/small.png", -1)
+	require.NoError(t, err)
+	require.Equal(t, []int{3}, s3.parts)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import "context"
+
+// TagImage adds the tag to the image, tagging twice changes nothing. The
+// tag is created in the tenant on first use.
+func (s *Storage) TagImage(ctx context.Context, tenant string, imageID int, tag string) error {
+	query := `WITH tag AS (
+					INSERT INTO tags (tenant, name)
+					VALUES ($1, $3)
+					ON CONFLICT (tenant, name) DO UPDATE SET name = EXCLUDED.name
+					RETURNING id
+				)
+				INSERT INTO image_tags (image_id, tag_id)
+				SELECT i.id, tag.id
+				FROM image_path i, tag
+				WHERE i.id=$2 AND i.tenant=$1
+				ON CONFLICT DO NOTHING`
+	_, err := s.DB.ExecContext(ctx, query, tenant, imageID, tag)
+	return err
+}
+
+// UntagImage returns sql.ErrNoRows when the image does not have the tag.
+func (s *Storage) UntagImage(ctx context.Context, tenant string, imageID int, tag string) error {
+	query := `DELETE FROM image_tags it
+				USING tags t
+				WHERE it.tag_id = t.id
+					AND t.tenant=$1 AND t.name=$3
+					AND it.image_id=$2`
+	res, err := s.DB.ExecContext(ctx, query, tenant, imageID, tag)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
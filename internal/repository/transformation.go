@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+)
+
+var ErrAlreadyExists = fmt.Errorf("already exists")
+
+func (s *Storage) CreateTransformation(ctx context.Context, t model.Transformation) (model.Transformation, error) {
+	steps, err := json.Marshal(t.Steps)
+	if err != nil {
+		return model.Transformation{}, err
+	}
+
+	now := time.Now()
+	query := `INSERT INTO transformations (name, steps, format, quality, watermark_path, created_at, updated_at, tenant)
+				VALUES ($1, $2, $3, $4, $5, $6, $6, $7)
+				ON CONFLICT (tenant, name) DO NOTHING
+				RETURNING created_at, updated_at`
+	res := s.DB.QueryRowContext(ctx, query, t.Name, string(steps), t.Format, t.Quality, t.WatermarkPath, now, t.Tenant)
+	err = res.Scan(&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Transformation{}, ErrAlreadyExists
+		}
+		return model.Transformation{}, err
+	}
+	return t, nil
+}
+
+func (s *Storage) GetTransformation(ctx context.Context, tenant, name string) (model.Transformation, error) {
+	query := `SELECT name, steps, format, quality, watermark_path, created_at, updated_at, tenant
+				FROM transformations
+				WHERE name=$1 AND tenant=$2`
+	res, err := s.DB.QueryContext(ctx, query, name, tenant)
+	if err != nil {
+		return model.Transformation{}, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	if !res.Next() {
+		if err := res.Err(); err != nil {
+			return model.Transformation{}, err
+		}
+		return model.Transformation{}, sql.ErrNoRows
+	}
+	return scanTransformation(res)
+}
+
+func (s *Storage) GetTransformations(ctx context.Context, tenant string) ([]model.Transformation, error) {
+	query := `SELECT name, steps, format, quality, watermark_path, created_at, updated_at, tenant
+				FROM transformations
+				WHERE tenant=$1
+				ORDER BY name`
+	res, err := s.DB.QueryContext(ctx, query, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	transformations := make([]model.Transformation, 0)
+	for res.Next() {
+		t, err := scanTransformation(res)
+		if err != nil {
+			return nil, err
+		}
+		transformations = append(transformations, t)
+	}
+	return transformations, res.Err()
+}
+
+func (s *Storage) UpdateTransformation(ctx context.Context, t model.Transformation) (model.Transformation, error) {
+	steps, err := json.Marshal(t.Steps)
+	if err != nil {
+		return model.Transformation{}, err
+	}
+
+	query := `UPDATE transformations
+				SET steps=$1,
+					format=$2,
+					quality=$3,
+					watermark_path=$4,
+					updated_at=$5
+				WHERE name=$6 AND tenant=$7
+				RETURNING created_at, updated_at`
+	res := s.DB.QueryRowContext(ctx, query, string(steps), t.Format, t.Quality, t.WatermarkPath, time.Now(), t.Name, t.Tenant)
+	err = res.Scan(&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return model.Transformation{}, err
+	}
+	return t, nil
+}
+
+func (s *Storage) DeleteTransformation(ctx context.Context, tenant, name string) error {
+	query := `DELETE
+				FROM transformations
+				WHERE name=$1 AND tenant=$2`
+	res, err := s.DB.ExecContext(ctx, query, name, tenant)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanTransformation(rows *sql.Rows) (model.Transformation, error) {
+	var t model.Transformation
+	var steps []byte
+	err := rows.Scan(&t.Name, &steps, &t.Format, &t.Quality, &t.WatermarkPath, &t.CreatedAt, &t.UpdatedAt, &t.Tenant)
+	if err != nil {
+		return model.Transformation{}, err
+	}
+
+	err = json.Unmarshal(steps, &t.Steps)
+	if err != nil {
+		return model.Transformation{}, err
+	}
+	return t, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"ImageProcessor/internal/model"
+)
+
+var ErrQuotaExceeded = fmt.Errorf("quota exceeded")
+
+// GetUsage returns the storage used by the originals of the owner, anonymous
+// uploads are counted for the empty owner of the tenant.
+func (s *Storage) GetUsage(ctx context.Context, tenant, owner string) (model.Usage, error) {
+	query := `SELECT bytes, images
+				FROM storage_usage
+				WHERE tenant=$1 AND owner=$2`
+	var usage model.Usage
+	err := s.DB.QueryRowContext(ctx, query, tenant, owner).Scan(&usage.Bytes, &usage.Images)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.Usage{}, err
+	}
+	return usage, nil
+}
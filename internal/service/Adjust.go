@@ -0,0 +1,192 @@
+package service
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"math"
+
+	"ImageProcessor/internal/model"
+)
+
+type colorFunc func(c color.NRGBA) color.NRGBA
+
+type colorMatrix [3][3]float64
+
+var identityMatrix = colorMatrix{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+var sepiaMatrix = colorMatrix{
+	{0.393, 0.769, 0.189},
+	{0.349, 0.686, 0.168},
+	{0.272, 0.534, 0.131},
+}
+
+// ValidateAdjust checks that at least one adjustment is requested and that
+// brightness, contrast and saturation lie in [-1, 1], gamma is positive and
+// all values are finite.
+func ValidateAdjust(params model.ProcessingParams) error {
+	if params.Grayscale == nil && params.Sepia == nil && params.Invert == nil &&
+		params.Brightness == nil && params.Contrast == nil && params.Saturation == nil &&
+		params.Hue == nil && params.Gamma == nil {
+		return ErrBadParameters
+	}
+	if !finiteParams(params) {
+		return ErrBadParameters
+	}
+
+	for _, v := range []*float64{params.Brightness, params.Contrast, params.Saturation} {
+		if v != nil && (*v < -1 || *v > 1) {
+			return ErrBadParameters
+		}
+	}
+
+	if params.Gamma != nil && *params.Gamma <= 0 {
+		return ErrBadParameters
+	}
+	return nil
+}
+
+// adjust applies color adjustments in a fixed order: gamma, brightness,
+// contrast, saturation, hue, grayscale, sepia, invert. GIF frames keep their
+// pixels and only the palettes are remapped.
+func adjust(is ImageService) (model.ImageInRepo, error) {
+	if err := ValidateAdjust(is.Img.Parameters); err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	fn := adjustFunc(is.Img.Parameters)
+
+	return process(is, "adjusted", func(img image.Image) (image.Image, error) {
+		return mapColors(img, fn), nil
+	}, func(gifData *gif.GIF) (*gif.GIF, error) {
+		mapGIFPalettes(gifData, fn)
+		return gifData, nil
+	})
+}
+
+func adjustFunc(params model.ProcessingParams) colorFunc {
+	var lut [256]uint8
+	for i := range lut {
+		v := float64(i) / 255
+		if params.Gamma != nil {
+			v = math.Pow(v, 1 / *params.Gamma)
+		}
+		if params.Brightness != nil {
+			v += *params.Brightness
+		}
+		if params.Contrast != nil {
+			v = (v-0.5)*(1+*params.Contrast) + 0.5
+		}
+		lut[i] = clampUint8(v * 255)
+	}
+
+	m := identityMatrix
+	if params.Saturation != nil {
+		m = saturateMatrix(1 + *params.Saturation).mul(m)
+	}
+	if params.Hue != nil {
+		m = hueRotateMatrix(*params.Hue).mul(m)
+	}
+	if params.Grayscale != nil && *params.Grayscale {
+		m = saturateMatrix(0).mul(m)
+	}
+	if params.Sepia != nil && *params.Sepia {
+		m = sepiaMatrix.mul(m)
+	}
+
+	invert := params.Invert != nil && *params.Invert
+
+	return func(c color.NRGBA) color.NRGBA {
+		r, g, b := float64(lut[c.R]), float64(lut[c.G]), float64(lut[c.B])
+		out := color.NRGBA{
+			R: clampUint8(m[0][0]*r + m[0][1]*g + m[0][2]*b),
+			G: clampUint8(m[1][0]*r + m[1][1]*g + m[1][2]*b),
+			B: clampUint8(m[2][0]*r + m[2][1]*g + m[2][2]*b),
+			A: c.A,
+		}
+		if invert {
+			out.R, out.G, out.B = 255-out.R, 255-out.G, 255-out.B
+		}
+		return out
+	}
+}
+
+func mapColors(img image.Image, fn colorFunc) *image.NRGBA {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			out.SetNRGBA(x, y, fn(c))
+		}
+	}
+	return out
+}
+
+// mapGIFPalettes remaps every palette once. Frames sharing the global color
+// table keep sharing it, so the encoder does not emit local tables for them.
+func mapGIFPalettes(gifData *gif.GIF, fn colorFunc) {
+	mapped := make(map[*color.Color]color.Palette)
+	mapPalette := func(p color.Palette) color.Palette {
+		if len(p) == 0 {
+			return p
+		}
+		if out, ok := mapped[&p[0]]; ok {
+			return out
+		}
+		out := make(color.Palette, len(p))
+		for i, c := range p {
+			out[i] = fn(color.NRGBAModel.Convert(c).(color.NRGBA))
+		}
+		mapped[&p[0]] = out
+		return out
+	}
+
+	if global, ok := gifData.Config.ColorModel.(color.Palette); ok {
+		gifData.Config.ColorModel = mapPalette(global)
+	}
+	for _, frame := range gifData.Image {
+		frame.Palette = mapPalette(frame.Palette)
+	}
+}
+
+func (a colorMatrix) mul(b colorMatrix) colorMatrix {
+	var out colorMatrix
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				out[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return out
+}
+
+func saturateMatrix(s float64) colorMatrix {
+	return colorMatrix{
+		{0.213 + 0.787*s, 0.715 - 0.715*s, 0.072 - 0.072*s},
+		{0.213 - 0.213*s, 0.715 + 0.285*s, 0.072 - 0.072*s},
+		{0.213 - 0.213*s, 0.715 - 0.715*s, 0.072 + 0.928*s},
+	}
+}
+
+func hueRotateMatrix(degrees float64) colorMatrix {
+	rad := degrees * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+	return colorMatrix{
+		{0.213 + cos*0.787 - sin*0.213, 0.715 - cos*0.715 - sin*0.715, 0.072 - cos*0.072 + sin*0.928},
+		{0.213 - cos*0.213 + sin*0.143, 0.715 + cos*0.285 + sin*0.140, 0.072 - cos*0.072 - sin*0.283},
+		{0.213 - cos*0.213 - sin*0.787, 0.715 - cos*0.715 + sin*0.715, 0.072 + cos*0.928 + sin*0.072},
+	}
+}
+
+func clampUint8(v float64) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"ImageProcessor/internal/model"
+)
+
+const (
+	defaultBorderColor = "#000000"
+	maxCanvasPadding   = 4096
+)
+
+func ValidatePad(params model.ProcessingParams) error {
+	if params.Padding == nil || *params.Padding <= 0 || *params.Padding > maxCanvasPadding {
+		return ErrBadParameters
+	}
+	if params.Background != nil {
+		if _, err := ParseColor(*params.Background); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidateBorder(params model.ProcessingParams) error {
+	if params.BorderWidth == nil || *params.BorderWidth <= 0 || *params.BorderWidth > maxCanvasPadding {
+		return ErrBadParameters
+	}
+	if params.BorderColor != nil {
+		if _, err := ParseColor(*params.BorderColor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidateRound(params model.ProcessingParams) error {
+	circle := params.Circle != nil && *params.Circle
+	if !circle && (params.CornerRadius == nil || *params.CornerRadius <= 0) {
+		return ErrBadParameters
+	}
+	return nil
+}
+
+// pad extends the canvas by padding pixels on every side.
+func pad(is ImageService) (model.ImageInRepo, error) {
+	params := is.Img.Parameters
+	if err := ValidatePad(params); err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	background := defaultBackground
+	if params.Background != nil {
+		background = *params.Background
+	}
+	bg, err := ParseColor(background)
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	padding := *params.Padding
+
+	return transform(is, "padded", func(img image.Image) (image.Image, error) {
+		b := img.Bounds()
+		out := image.NewRGBA(image.Rect(0, 0, b.Dx()+2*padding, b.Dy()+2*padding))
+		draw.Draw(out, out.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+		draw.Draw(out, image.Rect(padding, padding, padding+b.Dx(), padding+b.Dy()), img, b.Min, draw.Src)
+		return out, nil
+	})
+}
+
+// border paints a frame of the given width over the edges of the image.
+func border(is ImageService) (model.ImageInRepo, error) {
+	params := is.Img.Parameters
+	if err := ValidateBorder(params); err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	borderColor := defaultBorderColor
+	if params.BorderColor != nil {
+		borderColor = *params.BorderColor
+	}
+	c, err := ParseColor(borderColor)
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	width := *params.BorderWidth
+
+	return transform(is, "bordered", func(img image.Image) (image.Image, error) {
+		out := toRGBA(img)
+		b := out.Bounds()
+		inner := image.Rect(width, width, b.Dx()-width, b.Dy()-width)
+
+		fill := image.NewUniform(c)
+		for _, r := range []image.Rectangle{
+			image.Rect(0, 0, b.Dx(), inner.Min.Y),
+			image.Rect(0, inner.Max.Y, b.Dx(), b.Dy()),
+			image.Rect(0, 0, inner.Min.X, b.Dy()),
+			image.Rect(inner.Max.X, 0, b.Dx(), b.Dy()),
+		} {
+			draw.Draw(out, r.Intersect(b), fill, image.Point{}, draw.Over)
+		}
+		return out, nil
+	})
+}
+
+// round makes the corners transparent. With circle set the image is cropped
+// to a centered square and masked with the inscribed circle.
+func round(is ImageService) (model.ImageInRepo, error) {
+	params := is.Img.Parameters
+	if err := ValidateRound(params); err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	circle := params.Circle != nil && *params.Circle
+
+	return transform(is, "rounded", func(img image.Image) (image.Image, error) {
+		src := toNRGBA(img)
+		b := src.Bounds()
+
+		if circle {
+			side := min(b.Dx(), b.Dy())
+			x0, y0 := (b.Dx()-side)/2, (b.Dy()-side)/2
+			src = toNRGBA(src.SubImage(image.Rect(x0, y0, x0+side, y0+side)))
+			roundCorners(src, float64(side)/2)
+			return src, nil
+		}
+
+		out := image.NewNRGBA(b)
+		copy(out.Pix, src.Pix)
+		radius := min(float64(*params.CornerRadius), float64(min(b.Dx(), b.Dy()))/2)
+		roundCorners(out, radius)
+		return out, nil
+	})
+}
+
+// roundCorners scales alpha by the antialiased coverage of every corner
+// pixel inside a circle of the given radius.
+func roundCorners(img *image.NRGBA, radius float64) {
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+
+			cx, cy := px, py
+			switch {
+			case px < radius:
+				cx = radius
+			case px > w-radius:
+				cx = w - radius
+			}
+			switch {
+			case py < radius:
+				cy = radius
+			case py > h-radius:
+				cy = h - radius
+			}
+			if cx == px || cy == py {
+				continue
+			}
+
+			coverage := radius - math.Hypot(px-cx, py-cy) + 0.5
+			if coverage >= 1 {
+				continue
+			}
+
+			i := img.PixOffset(b.Min.X+x, b.Min.Y+y)
+			if coverage <= 0 {
+				img.Pix[i+3] = 0
+				continue
+			}
+			img.Pix[i+3] = uint8(float64(img.Pix[i+3]) * coverage)
+		}
+	}
+}
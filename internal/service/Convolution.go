@@ -0,0 +1,302 @@
+package service
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"runtime"
+	"sync"
+
+	"ImageProcessor/internal/model"
+)
+
+const (
+	FilterBlur    = "blur"
+	FilterSharpen = "sharpen"
+	FilterEdge    = "edge"
+	FilterEmboss  = "emboss"
+	FilterCustom  = "custom"
+)
+
+const (
+	maxBlurRadius  = 50
+	maxSharpen     = 10
+	parallelPixels = 256 * 256
+)
+
+type kernel struct {
+	size   int
+	values []float64
+}
+
+var (
+	edgeKernel = kernel{size: 3, values: []float64{
+		-1, -1, -1,
+		-1, 8, -1,
+		-1, -1, -1,
+	}}
+	embossKernel = kernel{size: 3, values: []float64{
+		-2, -1, 0,
+		-1, 1, 1,
+		0, 1, 2,
+	}}
+)
+
+func ValidFilter(name string) bool {
+	switch name {
+	case FilterBlur, FilterSharpen, FilterEdge, FilterEmboss, FilterCustom:
+		return true
+	}
+	return false
+}
+
+// ValidateFilter checks the filter name and the parameters it depends on:
+// radius/sigma for blur and sharpen, amount for sharpen and a 3x3 or 5x5
+// kernel for custom.
+func ValidateFilter(params model.ProcessingParams) error {
+	if params.Filter == nil || !ValidFilter(*params.Filter) || !finiteParams(params) {
+		return ErrBadParameters
+	}
+	if params.Radius != nil && (*params.Radius < 1 || *params.Radius > maxBlurRadius) {
+		return ErrBadParameters
+	}
+	if params.Sigma != nil && (*params.Sigma <= 0 || *params.Sigma > maxBlurRadius) {
+		return ErrBadParameters
+	}
+	if params.Amount != nil && (*params.Amount <= 0 || *params.Amount > maxSharpen) {
+		return ErrBadParameters
+	}
+	if *params.Filter == FilterCustom && len(params.Kernel) != 9 && len(params.Kernel) != 25 {
+		return ErrBadParameters
+	}
+	return nil
+}
+
+func filter(is ImageService) (model.ImageInRepo, error) {
+	params := is.Img.Parameters
+	if err := ValidateFilter(params); err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	return transform(is, "filtered", func(img image.Image) (image.Image, error) {
+		return applyFilter(img, params), nil
+	})
+}
+
+func applyFilter(img image.Image, params model.ProcessingParams) image.Image {
+	radius, sigma := blurRadius(params)
+
+	switch *params.Filter {
+	case FilterBlur:
+		return gaussianBlur(toRGBA(img), radius, sigma)
+	case FilterSharpen:
+		amount := 1.0
+		if params.Amount != nil {
+			amount = *params.Amount
+		}
+		return unsharpMask(img, amount, radius, sigma)
+	case FilterEdge:
+		return convolve(img, edgeKernel)
+	case FilterEmboss:
+		return convolve(img, embossKernel)
+	default:
+		size := 3
+		if len(params.Kernel) == 25 {
+			size = 5
+		}
+		return convolve(img, kernel{size: size, values: normalizeKernel(params.Kernel)})
+	}
+}
+
+// blurRadius derives the missing one of radius and sigma, defaulting to
+// sigma 1 which covers three standard deviations. A derived radius is capped
+// at maxBlurRadius like a requested one.
+func blurRadius(params model.ProcessingParams) (int, float64) {
+	switch {
+	case params.Radius != nil && params.Sigma != nil:
+		return *params.Radius, *params.Sigma
+	case params.Radius != nil:
+		return *params.Radius, math.Max(float64(*params.Radius)/2, 0.5)
+	case params.Sigma != nil:
+		return min(int(math.Ceil(*params.Sigma*3)), maxBlurRadius), *params.Sigma
+	default:
+		return 3, 1
+	}
+}
+
+func normalizeKernel(values []float64) []float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	if sum == 0 {
+		return values
+	}
+
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = v / sum
+	}
+	return out
+}
+
+// gaussianBlur runs two separable passes over premultiplied pixels so
+// transparent areas do not bleed dark fringes into the result.
+func gaussianBlur(src *image.RGBA, radius int, sigma float64) *image.RGBA {
+	weights := make([]float64, 2*radius+1)
+	var sum float64
+	for i := -radius; i <= radius; i++ {
+		w := math.Exp(-float64(i*i) / (2 * sigma * sigma))
+		weights[i+radius] = w
+		sum += w
+	}
+	for i := range weights {
+		weights[i] /= sum
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	tmp := image.NewRGBA(image.Rect(0, 0, w, h))
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	parallelRows(w, h, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := 0; x < w; x++ {
+				var acc [4]float64
+				for k, weight := range weights {
+					sx := clampInt(x+k-radius, 0, w-1)
+					i := src.PixOffset(sx, y)
+					for c := 0; c < 4; c++ {
+						acc[c] += float64(src.Pix[i+c]) * weight
+					}
+				}
+				i := tmp.PixOffset(x, y)
+				for c := 0; c < 4; c++ {
+					tmp.Pix[i+c] = clampUint8(acc[c])
+				}
+			}
+		}
+	})
+
+	parallelRows(w, h, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := 0; x < w; x++ {
+				var acc [4]float64
+				for k, weight := range weights {
+					sy := clampInt(y+k-radius, 0, h-1)
+					i := tmp.PixOffset(x, sy)
+					for c := 0; c < 4; c++ {
+						acc[c] += float64(tmp.Pix[i+c]) * weight
+					}
+				}
+				i := out.PixOffset(x, y)
+				for c := 0; c < 4; c++ {
+					out.Pix[i+c] = clampUint8(acc[c])
+				}
+			}
+		}
+	})
+
+	return out
+}
+
+// unsharpMask adds amount times the difference between the image and its
+// blurred copy to the color channels, alpha is kept as is.
+func unsharpMask(img image.Image, amount float64, radius int, sigma float64) *image.NRGBA {
+	src := toNRGBA(img)
+	blurred := toNRGBA(gaussianBlur(toRGBA(src), radius, sigma))
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	parallelRows(w, h, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := 0; x < w; x++ {
+				i := src.PixOffset(x, y)
+				for c := 0; c < 3; c++ {
+					v := float64(src.Pix[i+c])
+					out.Pix[i+c] = clampUint8(v + amount*(v-float64(blurred.Pix[i+c])))
+				}
+				out.Pix[i+3] = src.Pix[i+3]
+			}
+		}
+	})
+
+	return out
+}
+
+// convolve applies k to the color channels with clamped edges, alpha is kept
+// as is so kernels summing to zero do not erase the image.
+func convolve(img image.Image, k kernel) *image.NRGBA {
+	src := toNRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	half := k.size / 2
+
+	parallelRows(w, h, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := 0; x < w; x++ {
+				var acc [3]float64
+				for ky := 0; ky < k.size; ky++ {
+					sy := clampInt(y+ky-half, 0, h-1)
+					for kx := 0; kx < k.size; kx++ {
+						sx := clampInt(x+kx-half, 0, w-1)
+						weight := k.values[ky*k.size+kx]
+						i := src.PixOffset(sx, sy)
+						for c := 0; c < 3; c++ {
+							acc[c] += float64(src.Pix[i+c]) * weight
+						}
+					}
+				}
+				i := out.PixOffset(x, y)
+				for c := 0; c < 3; c++ {
+					out.Pix[i+c] = clampUint8(acc[c])
+				}
+				out.Pix[i+3] = src.Pix[i+3]
+			}
+		}
+	})
+
+	return out
+}
+
+// parallelRows splits the rows of large images between goroutines, small
+// images are processed in the calling goroutine.
+func parallelRows(w, h int, fn func(y0, y1 int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if w*h < parallelPixels || workers < 2 {
+		fn(0, h)
+		return
+	}
+
+	chunk := (h + workers - 1) / workers
+	var wg sync.WaitGroup
+	for y0 := 0; y0 < h; y0 += chunk {
+		y1 := min(y0+chunk, h)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(y0, y1)
+		}()
+	}
+	wg.Wait()
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Bounds().Min == (image.Point{}) {
+		return nrgba
+	}
+	bounds := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	return nrgba
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
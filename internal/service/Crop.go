@@ -0,0 +1,219 @@
+package service
+
+import (
+	"image"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+
+	"ImageProcessor/internal/model"
+)
+
+const (
+	GravityCenter = "center"
+	GravityNorth  = "north"
+	GravitySouth  = "south"
+	GravityEast   = "east"
+	GravityWest   = "west"
+	GravitySmart  = "smart"
+)
+
+const (
+	smartSampleSize = 256
+	smartCenterBias = 0.1
+)
+
+func ValidGravity(gravity string) bool {
+	switch gravity {
+	case GravityCenter, GravityNorth, GravitySouth, GravityEast, GravityWest, GravitySmart:
+		return true
+	}
+	return false
+}
+
+func gravityParam(params model.ProcessingParams, fallback string) (string, error) {
+	if params.Gravity == nil || *params.Gravity == "" {
+		return fallback, nil
+	}
+	if !ValidGravity(*params.Gravity) {
+		return "", ErrBadParameters
+	}
+	return *params.Gravity, nil
+}
+
+// crop cuts a width x height window placed by gravity, sizes larger than the
+// image are clamped to it.
+func crop(is ImageService) (model.ImageInRepo, error) {
+	params := is.Img.Parameters
+	if params.Width == nil || params.Height == nil || !ValidSize(*params.Width, *params.Height) {
+		return model.ImageInRepo{}, ErrBadParameters
+	}
+
+	gravity, err := gravityParam(params, GravityCenter)
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	var window *image.Rectangle
+	return transform(is, "cropped", func(img image.Image) (image.Image, error) {
+		b := img.Bounds()
+		if window == nil {
+			w := cropWindow(img, min(*params.Width, b.Dx()), min(*params.Height, b.Dy()), gravity)
+			window = &w
+		}
+		return toRGBA(toRGBA(img).SubImage(*window)), nil
+	})
+}
+
+// thumbnail scales the image to width x height according to the resize mode.
+// fill (the default) keeps the aspect ratio: the largest window of the target
+// proportions is chosen by gravity (smart by default) and then scaled, frames
+// of a GIF share the window of the first frame. fit scales the image to fit
+// inside the box and stretch ignores the aspect ratio.
+func thumbnail(is ImageService) (model.ImageInRepo, error) {
+	params := is.Img.Parameters
+	if params.Width == nil || params.Height == nil || !ValidSize(*params.Width, *params.Height) {
+		return model.ImageInRepo{}, ErrBadParameters
+	}
+
+	mode := ResizeFill
+	if params.ResizeMode != nil {
+		mode = *params.ResizeMode
+	}
+	if !ValidResizeMode(mode) {
+		return model.ImageInRepo{}, ErrBadParameters
+	}
+
+	gravity, err := gravityParam(params, GravitySmart)
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	width, height := *params.Width, *params.Height
+
+	var window *image.Rectangle
+	return transform(is, "thumbnails", func(img image.Image) (image.Image, error) {
+		switch mode {
+		case ResizeFit:
+			w, h := fitSize(img.Bounds(), width, height)
+			return scaleAndSharpen(img, w, h, params)
+		case ResizeStretch:
+			return scaleAndSharpen(img, width, height, params)
+		}
+
+		if window == nil {
+			w, h := fillSize(img.Bounds(), width, height)
+			r := cropWindow(img, w, h, gravity)
+			window = &r
+		}
+		return scaleAndSharpen(toRGBA(img).SubImage(*window), width, height, params)
+	})
+}
+
+// fitSize returns the largest size with the proportions of bounds that fits
+// into width x height.
+func fitSize(bounds image.Rectangle, width, height int) (int, int) {
+	w := width
+	h := bounds.Dy() * width / bounds.Dx()
+	if h > height {
+		h = height
+		w = bounds.Dx() * height / bounds.Dy()
+	}
+	return max(w, 1), max(h, 1)
+}
+
+// fillSize returns the largest size with the width:height proportions that
+// fits into bounds.
+func fillSize(bounds image.Rectangle, width, height int) (int, int) {
+	w := bounds.Dx()
+	h := w * height / width
+	if h > bounds.Dy() {
+		h = bounds.Dy()
+		w = h * width / height
+	}
+	return max(w, 1), max(h, 1)
+}
+
+// cropWindow places a w x h window inside img according to gravity. The
+// result is relative to the image origin.
+func cropWindow(img image.Image, w, h int, gravity string) image.Rectangle {
+	b := img.Bounds()
+	x := (b.Dx() - w) / 2
+	y := (b.Dy() - h) / 2
+
+	switch gravity {
+	case GravityNorth:
+		y = 0
+	case GravitySouth:
+		y = b.Dy() - h
+	case GravityWest:
+		x = 0
+	case GravityEast:
+		x = b.Dx() - w
+	case GravitySmart:
+		return smartWindow(img, w, h)
+	}
+
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// smartWindow picks the w x h window with the highest edge energy. The image
+// is sampled down to smartSampleSize, Sobel gradient magnitudes are summed
+// with an integral image and windows far from the center are slightly
+// penalized, so flat images fall back to a center crop.
+func smartWindow(img image.Image, w, h int) image.Rectangle {
+	b := img.Bounds()
+	factor := max(1, (max(b.Dx(), b.Dy())+smartSampleSize-1)/smartSampleSize)
+	sw, sh := max(1, b.Dx()/factor), max(1, b.Dy()/factor)
+
+	small := image.NewGray(image.Rect(0, 0, sw, sh))
+	xdraw.ApproxBiLinear.Scale(small, small.Bounds(), img, b, xdraw.Src, nil)
+
+	integral := make([]float64, (sw+1)*(sh+1))
+	for y := 0; y < sh; y++ {
+		var row float64
+		for x := 0; x < sw; x++ {
+			row += sobel(small, x, y)
+			integral[(y+1)*(sw+1)+x+1] = integral[y*(sw+1)+x+1] + row
+		}
+	}
+
+	ww, wh := min(sw, max(1, w/factor)), min(sh, max(1, h/factor))
+	maxDist := math.Hypot(float64(sw-ww)/2, float64(sh-wh)/2)
+
+	bestX, bestY, bestScore := (sw-ww)/2, (sh-wh)/2, math.Inf(-1)
+	for y := 0; y+wh <= sh; y++ {
+		for x := 0; x+ww <= sw; x++ {
+			energy := integral[(y+wh)*(sw+1)+x+ww] - integral[y*(sw+1)+x+ww] -
+				integral[(y+wh)*(sw+1)+x] + integral[y*(sw+1)+x]
+
+			score := energy
+			if maxDist > 0 {
+				dist := math.Hypot(float64(x)-float64(sw-ww)/2, float64(y)-float64(sh-wh)/2)
+				score = energy*(1-smartCenterBias*dist/maxDist) - dist*1e-9
+			}
+			if score > bestScore {
+				bestX, bestY, bestScore = x, y, score
+			}
+		}
+	}
+
+	x := min(bestX*factor, b.Dx()-w)
+	y := min(bestY*factor, b.Dy()-h)
+	return image.Rect(x, y, x+w, y+h)
+}
+
+func sobel(img *image.Gray, x, y int) float64 {
+	b := img.Bounds()
+	if x == 0 || y == 0 || x == b.Dx()-1 || y == b.Dy()-1 {
+		return 0
+	}
+
+	p := func(dx, dy int) float64 {
+		return float64(img.GrayAt(x+dx, y+dy).Y)
+	}
+
+	gx := p(1, -1) + 2*p(1, 0) + p(1, 1) - p(-1, -1) - 2*p(-1, 0) - p(-1, 1)
+	gy := p(-1, 1) + 2*p(0, 1) + p(1, 1) - p(-1, -1) - 2*p(0, -1) - p(1, -1)
+	return math.Abs(gx) + math.Abs(gy)
+}
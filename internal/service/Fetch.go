@@ -0,0 +1,164 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/repository"
+)
+
+const maxFetchRedirects = 5
+
+var (
+	ErrFetchForbidden = fmt.Errorf("fetching from private addresses is forbidden")
+	ErrFetchTooLarge  = fmt.Errorf("fetched image is too large")
+	ErrFetchNotImage  = fmt.Errorf("fetched content is not a supported image")
+	ErrFetchFormat    = fmt.Errorf("fetched image format is not allowed")
+)
+
+// fetchContentTypes maps the accepted content types to their formats.
+var fetchContentTypes = map[string]string{
+	"image/jpeg": FormatJPEG,
+	"image/png":  FormatPNG,
+	"image/gif":  FormatGIF,
+}
+
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Fetcher downloads images from other HTTP servers for POST /upload/url.
+// Addresses are checked after DNS resolution on every connection, so
+// redirects and rebinding cannot reach private networks unless AllowPrivate
+// is set. The format of the fetched content is checked against Tenants
+// when it is set.
+type Fetcher struct {
+	MaxSize      int64
+	AllowPrivate bool
+	Tenants      *Tenants
+
+	client *http.Client
+}
+
+func NewFetcher(maxSize int64, timeout time.Duration) *Fetcher {
+	f := &Fetcher{MaxSize: maxSize}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || (!f.AllowPrivate && privateIP(ip)) {
+				return ErrFetchForbidden
+			}
+			return nil
+		},
+	}
+
+	f.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxFetchRedirects {
+				return fmt.Errorf("too many redirects")
+			}
+			return ValidateSourceURL(req.URL.String())
+		},
+	}
+	return f
+}
+
+// ValidateSourceURL accepts absolute http and https URLs.
+func ValidateSourceURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url")
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("only http and https urls are supported")
+	}
+	if u.User != nil {
+		return fmt.Errorf("urls with credentials are not supported")
+	}
+	return nil
+}
+
+// Fetch downloads rawURL and stores it as objectName, the image must be in a
+// format the tenant may upload. It returns the size of the stored object.
+func (f *Fetcher) Fetch(ctx context.Context, store repository.ImageStore, tenant, rawURL, objectName string) (int64, error) {
+	err := ValidateSourceURL(rawURL)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "image/jpeg, image/png, image/gif")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("fetch %s: status %d", rawURL, resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if _, ok := fetchContentTypes[mediaType]; !ok {
+		return 0, ErrFetchNotImage
+	}
+	if resp.ContentLength > f.MaxSize {
+		return 0, ErrFetchTooLarge
+	}
+
+	buf := &bytes.Buffer{}
+	n, err := buf.ReadFrom(io.LimitReader(resp.Body, f.MaxSize+1))
+	if err != nil {
+		return 0, err
+	}
+	if n > f.MaxSize {
+		return 0, ErrFetchTooLarge
+	}
+	format, ok := fetchContentTypes[http.DetectContentType(buf.Bytes())]
+	if !ok {
+		return 0, ErrFetchNotImage
+	}
+	if f.Tenants != nil && !f.Tenants.AllowsFormat(tenant, format) {
+		return 0, fmt.Errorf("%w: %s", ErrFetchFormat, format)
+	}
+
+	err = store.Upload(ctx, buf, objectName, n)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func privateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || cgnat.Contains(ip)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"image"
+
+	xdraw "golang.org/x/image/draw"
+
+	"ImageProcessor/internal/model"
+)
+
+const (
+	InterpolationNearest        = "nearest"
+	InterpolationApproxBiLinear = "approx-bilinear"
+	InterpolationBiLinear       = "bilinear"
+	InterpolationCatmullRom     = "catmull-rom"
+)
+
+var interpolators = map[string]xdraw.Interpolator{
+	InterpolationNearest:        xdraw.NearestNeighbor,
+	InterpolationApproxBiLinear: xdraw.ApproxBiLinear,
+	InterpolationBiLinear:       xdraw.BiLinear,
+	InterpolationCatmullRom:     xdraw.CatmullRom,
+}
+
+func ValidInterpolation(name string) bool {
+	_, ok := interpolators[name]
+	return ok
+}
+
+// interpolator picks the kernel requested in params. Without an explicit choice
+// downscales use Catmull-Rom to keep detail and upscales use bilinear.
+func interpolator(params model.ProcessingParams, src, dst image.Rectangle) (xdraw.Interpolator, error) {
+	if params.Interpolation == nil || *params.Interpolation == "" {
+		if dst.Dx() < src.Dx() || dst.Dy() < src.Dy() {
+			return xdraw.CatmullRom, nil
+		}
+		return xdraw.BiLinear, nil
+	}
+
+	interp, ok := interpolators[*params.Interpolation]
+	if !ok {
+		return nil, ErrBadParameters
+	}
+	return interp, nil
+}
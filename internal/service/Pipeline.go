@@ -0,0 +1,245 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+)
+
+const TypePipeline = "pipeline"
+
+const (
+	maxPipelineSteps      = 10
+	maxTransformationName = 64
+	pipelineQuality       = 100
+	uploadWatermarks      = "watermarks/"
+)
+
+var transformationName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func ValidTransformationName(name string) bool {
+	return len(name) <= maxTransformationName && transformationName.MatchString(name)
+}
+
+// ValidateTransformation checks the name, the output options and every step
+// of a transformation. Steps cannot name a watermark object, watermark steps
+// use the watermark uploaded with the transformation.
+func ValidateTransformation(t model.Transformation) error {
+	if !ValidTransformationName(t.Name) {
+		return fmt.Errorf("invalid transformation name")
+	}
+	if len(t.Steps) == 0 || len(t.Steps) > maxPipelineSteps {
+		return fmt.Errorf("transformation must have from 1 to %d steps", maxPipelineSteps)
+	}
+	if t.Format != "" && !ValidFormat(t.Format) {
+		return fmt.Errorf("unsupported format %q", t.Format)
+	}
+	if t.Quality < 0 || t.Quality > 100 {
+		return fmt.Errorf("quality must be between 1 and 100")
+	}
+
+	for i, step := range t.Steps {
+		if step.Parameters.WatermarkPath != nil {
+			return fmt.Errorf("step %d (%s): watermark_path cannot be set, upload a watermark file", i+1, step.TypeProcessing)
+		}
+		if step.TypeProcessing == "watermark" && t.WatermarkPath != "" {
+			step.Parameters.WatermarkPath = &t.WatermarkPath
+		}
+		if err := ValidateStep(step); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, step.TypeProcessing, err)
+		}
+	}
+	return nil
+}
+
+// ValidateStep checks the parameters of a single operation without running
+// it.
+func ValidateStep(step model.Step) error {
+	params := step.Parameters
+	if !finiteParams(params) {
+		return ErrBadParameters
+	}
+	if params.Interpolation != nil && !ValidInterpolation(*params.Interpolation) {
+		return ErrBadParameters
+	}
+	if params.Sharpen != nil && (*params.Sharpen <= 0 || *params.Sharpen > maxSharpen) {
+		return ErrBadParameters
+	}
+	if _, err := gravityParam(params, GravityCenter); err != nil {
+		return err
+	}
+	if err := ValidateOutput(params); err != nil {
+		return err
+	}
+
+	switch step.TypeProcessing {
+	case "resize", "crop", "thumbnail":
+		if params.Width == nil || params.Height == nil || !ValidSize(*params.Width, *params.Height) {
+			return ErrBadParameters
+		}
+		if params.ResizeMode != nil && !ValidResizeMode(*params.ResizeMode) {
+			return ErrBadParameters
+		}
+	case "watermark":
+		if params.WatermarkPath == nil {
+			return ErrBadParameters
+		}
+	case "rotate":
+		if params.Angle == nil && params.Flip == nil {
+			return ErrBadParameters
+		}
+		if params.Flip != nil && !ValidFlip(*params.Flip) {
+			return ErrBadParameters
+		}
+		if params.Background != nil {
+			if _, err := ParseColor(*params.Background); err != nil {
+				return err
+			}
+		}
+	case "adjust":
+		return ValidateAdjust(params)
+	case "filter":
+		return ValidateFilter(params)
+	case "redact":
+		return ValidateRedactions(params.Redactions)
+	case "trim":
+		return ValidateTrim(params)
+	case "pad":
+		return ValidatePad(params)
+	case "border":
+		return ValidateBorder(params)
+	case "round":
+		return ValidateRound(params)
+	default:
+		return ErrUnknowMode
+	}
+	return nil
+}
+
+// ApplyTransformation turns the task into a pipeline running the steps of t.
+// The output format and quality of t apply to the last step. Watermark steps
+// always get the watermark of t, whatever the stored steps say.
+func ApplyTransformation(task *model.ImageTask, t model.Transformation) {
+	name := t.Name
+	task.TypeProcessing = TypePipeline
+	task.Parameters = model.ProcessingParams{Preset: &name}
+	if t.Format != "" {
+		format := t.Format
+		task.Parameters.Format = &format
+	}
+	if t.Quality != 0 {
+		quality := t.Quality
+		task.Parameters.Quality = &quality
+	}
+
+	task.Steps = make([]model.Step, len(t.Steps))
+	copy(task.Steps, t.Steps)
+	for i, step := range task.Steps {
+		task.Steps[i].Parameters.WatermarkPath = nil
+		if step.TypeProcessing == "watermark" && t.WatermarkPath != "" {
+			watermarkPath := t.WatermarkPath
+			task.Steps[i].Parameters.WatermarkPath = &watermarkPath
+		}
+	}
+}
+
+// pipeline runs the steps one after another, every step reads the result of
+// the previous one. Intermediate results are saved with maximum quality and
+// removed once the next step has read them.
+func pipeline(is ImageService) (model.ImageInRepo, error) {
+	steps := is.Img.Steps
+	if len(steps) == 0 || len(steps) > maxPipelineSteps {
+		return model.ImageInRepo{}, ErrBadParameters
+	}
+
+	source := is.Img.UploadsPath
+	result := &model.ProcessingResult{}
+
+	var res model.ImageInRepo
+	for i, step := range steps {
+		if step.TypeProcessing == TypePipeline {
+			return model.ImageInRepo{}, ErrBadParameters
+		}
+
+		sub := is
+		sub.Img.TypeProcessing = step.TypeProcessing
+		sub.Img.Parameters = step.Parameters
+		sub.Img.UploadsPath = source
+		sub.Img.Steps = nil
+		if i < len(steps)-1 {
+			sub.OutputName = ""
+		}
+
+		if i == len(steps)-1 {
+			if is.Img.Parameters.Format != nil {
+				sub.Img.Parameters.Format = is.Img.Parameters.Format
+			}
+			if is.Img.Parameters.Quality != nil {
+				sub.Img.Parameters.Quality = is.Img.Parameters.Quality
+			}
+		} else if sub.Img.Parameters.Quality == nil {
+			quality := pipelineQuality
+			sub.Img.Parameters.Quality = &quality
+		}
+
+		stepRes, err := ProcessImage(sub)
+		if i > 0 {
+			if err := is.ImageStorage.Delete(is.Ctx, source); err != nil {
+				zlog.Logger.Error().Msg(err.Error())
+			}
+		}
+		if err != nil {
+			return model.ImageInRepo{}, fmt.Errorf("step %d (%s): %w", i+1, step.TypeProcessing, err)
+		}
+
+		if stepRes.Result != nil {
+			result.Redactions = append(result.Redactions, stepRes.Result.Redactions...)
+			if stepRes.Result.TrimBox != nil {
+				result.TrimBox = stepRes.Result.TrimBox
+			}
+		}
+
+		source = stepRes.ProcessedPath
+		res = stepRes
+	}
+
+	res.UploadsPath = is.Img.UploadsPath
+	res.Result = nil
+	if len(result.Redactions) > 0 || result.TrimBox != nil {
+		res.Result = result
+	}
+	return res, nil
+}
+
+// ownsWatermark reports whether the watermark was uploaded together with the
+// image and should be removed after processing. Watermarks of transformations
+// are shared between uploads and kept.
+func ownsWatermark(tenant, path string) bool {
+	return strings.HasPrefix(path, repository.TenantKey(tenant, uploadWatermarks))
+}
+
+// finiteParams reports whether every float parameter is a finite number.
+// NaN passes the range checks of the validators and Inf breaks geometry.
+func finiteParams(params model.ProcessingParams) bool {
+	values := append([]float64{}, params.Kernel...)
+	for _, v := range []*float64{
+		params.Angle, params.Brightness, params.Contrast, params.Saturation, params.Hue,
+		params.Gamma, params.Sigma, params.Amount, params.Sharpen,
+	} {
+		if v != nil {
+			values = append(values, *v)
+		}
+	}
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"ImageProcessor/internal/model"
+)
+
+const (
+	ResizeFill    = "fill"
+	ResizeFit     = "fit"
+	ResizeStretch = "stretch"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+)
+
+const defaultQuality = 90
+
+// MaxImageSize is the largest width and height of presets, resizes and crops.
+const MaxImageSize = 4096
+
+var ErrUnknownPreset = fmt.Errorf("unknown preset")
+
+func ValidResizeMode(mode string) bool {
+	switch mode {
+	case ResizeFill, ResizeFit, ResizeStretch:
+		return true
+	}
+	return false
+}
+
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatJPEG, FormatPNG, FormatGIF:
+		return true
+	}
+	return false
+}
+
+// ValidSize reports whether width x height is a size images may be resized
+// or cropped to. The limit keeps a single request from allocating a huge
+// canvas.
+func ValidSize(width, height int) bool {
+	return width > 0 && height > 0 && width <= MaxImageSize && height <= MaxImageSize
+}
+
+// ValidatePreset checks the size, the resize mode and the optional output
+// format and quality of a preset.
+func ValidatePreset(p model.Preset) error {
+	if p.Name == "" {
+		return fmt.Errorf("preset without name")
+	}
+	if !ValidSize(p.Width, p.Height) {
+		return fmt.Errorf("preset %s: bad size %dx%d", p.Name, p.Width, p.Height)
+	}
+	if !ValidResizeMode(p.Mode) {
+		return fmt.Errorf("preset %s: unsupported resize mode %q", p.Name, p.Mode)
+	}
+	if p.Format != "" && !ValidFormat(p.Format) {
+		return fmt.Errorf("preset %s: unsupported format %q", p.Name, p.Format)
+	}
+	if p.Quality < 0 || p.Quality > 100 {
+		return fmt.Errorf("preset %s: quality must be between 1 and 100", p.Name)
+	}
+	return nil
+}
+
+// Presets is an immutable set of thumbnail presets, it is safe for
+// concurrent use.
+type Presets struct {
+	byName      map[string]model.Preset
+	defaultName string
+}
+
+func NewPresets(presets []model.Preset, defaultName string) (*Presets, error) {
+	if len(presets) == 0 {
+		return nil, fmt.Errorf("no thumbnail presets")
+	}
+
+	byName := make(map[string]model.Preset, len(presets))
+	for _, p := range presets {
+		if err := ValidatePreset(p); err != nil {
+			return nil, err
+		}
+		if _, ok := byName[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate preset %s", p.Name)
+		}
+		byName[p.Name] = p
+	}
+
+	if defaultName == "" {
+		defaultName = presets[0].Name
+	}
+	if _, ok := byName[defaultName]; !ok {
+		return nil, fmt.Errorf("default preset %s: %w", defaultName, ErrUnknownPreset)
+	}
+
+	return &Presets{byName: byName, defaultName: defaultName}, nil
+}
+
+// Get returns the preset with the given name, an empty name selects the
+// default preset.
+func (p *Presets) Get(name string) (model.Preset, error) {
+	if name == "" {
+		name = p.defaultName
+	}
+	preset, ok := p.byName[name]
+	if !ok {
+		return model.Preset{}, ErrUnknownPreset
+	}
+	return preset, nil
+}
+
+func (p *Presets) Default() string {
+	return p.defaultName
+}
+
+// List returns the presets ordered by area and then by name.
+func (p *Presets) List() []model.Preset {
+	list := make([]model.Preset, 0, len(p.byName))
+	for _, preset := range p.byName {
+		list = append(list, preset)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		ai, aj := list[i].Width*list[i].Height, list[j].Width*list[j].Height
+		if ai != aj {
+			return ai < aj
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// ApplyPreset copies the preset into the task parameters, so the worker does
+// not need the preset configuration.
+func ApplyPreset(params *model.ProcessingParams, p model.Preset) {
+	width, height, mode := p.Width, p.Height, p.Mode
+	name := p.Name
+	params.Preset = &name
+	params.Width = &width
+	params.Height = &height
+	params.ResizeMode = &mode
+
+	if p.Format != "" {
+		format := p.Format
+		params.Format = &format
+	}
+	if p.Quality != 0 {
+		quality := p.Quality
+		params.Quality = &quality
+	}
+}
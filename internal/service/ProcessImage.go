@@ -4,16 +4,16 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"image"
 	"image/draw"
 	"image/gif"
 	"image/jpeg"
 	"image/png"
+	"io"
 
-	"github.com/google/uuid"
 	"github.com/wb-go/wbf/zlog"
-	xdraw "golang.org/x/image/draw"
 
 	"ImageProcessor/internal/model"
 	"ImageProcessor/internal/repository"
@@ -24,15 +24,12 @@ var (
 	ErrUnknowMode    = fmt.Errorf("unknow mode")
 )
 
-var (
-	ThumbnailsHeight = 150
-	ThumbnailsWidth  = 150
-)
-
 type ImageService struct {
 	Ctx          context.Context
 	ImageStorage repository.ImageStore
 	Img          model.ImageTask
+	// OutputName replaces the generated name of the result when set.
+	OutputName string
 }
 
 func ProcessImage(is ImageService) (model.ImageInRepo, error) {
@@ -42,230 +39,165 @@ func ProcessImage(is ImageService) (model.ImageInRepo, error) {
 	case "resize":
 		return resize(is)
 	case "thumbnail":
-		is.Img.Parameters.Height = &ThumbnailsHeight
-		is.Img.Parameters.Width = &ThumbnailsWidth
-		return resize(is)
+		return thumbnail(is)
+	case "rotate":
+		return rotate(is)
+	case "adjust":
+		return adjust(is)
+	case "filter":
+		return filter(is)
+	case "redact":
+		return redact(is)
+	case "trim":
+		return trim(is)
+	case "pad":
+		return pad(is)
+	case "border":
+		return border(is)
+	case "round":
+		return round(is)
+	case "crop":
+		return crop(is)
+	case TypePipeline:
+		return pipeline(is)
 	default:
 		return model.ImageInRepo{}, ErrUnknowMode
 	}
 }
 
 func resize(is ImageService) (model.ImageInRepo, error) {
-	baseFile, err := is.ImageStorage.Download(is.Ctx, is.Img.UploadsPath)
-	if err != nil {
-		return model.ImageInRepo{}, err
-	}
-	defer func() {
-		if err := baseFile.Close(); err != nil {
-			zlog.Logger.Error().Msg(err.Error())
-		}
-	}()
-
-	buf := &bytes.Buffer{}
-	_, err = buf.ReadFrom(baseFile)
-	if err != nil {
-		return model.ImageInRepo{}, err
-	}
-
-	gifReader := bytes.NewReader(buf.Bytes())
-
-	gifData, err := gif.DecodeAll(gifReader)
-	if err == nil {
-		return resizeGIF(is, gifData)
-	}
-
-	imageReader := bytes.NewReader(buf.Bytes())
-	baseImg, format, err := image.Decode(imageReader)
-	if err != nil {
-		return model.ImageInRepo{}, err
-	}
-
-	baseBounds := baseImg.Bounds()
 	if is.Img.Parameters.Height == nil || is.Img.Parameters.Width == nil {
 		return model.ImageInRepo{}, ErrBadParameters
 	}
 
 	newWidth := *is.Img.Parameters.Width
 	newHeight := *is.Img.Parameters.Height
-	resizedImage := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
-
-	xdraw.BiLinear.Scale(resizedImage, resizedImage.Bounds(), baseImg, baseBounds.Bounds(), draw.Over, nil)
-
-	var outFileName string
-
-	switch is.Img.TypeProcessing {
-	case "resize":
-		outFileName = fmt.Sprintf("processed/resized/%s-resized.%s", uuid.New().String(), format)
-	case "thumbnail":
-		outFileName = fmt.Sprintf("processed/thumbnails/%s-thumbnails.%s", uuid.New().String(), format)
-	}
-
-	err = saveImage(is, outFileName, resizedImage, format)
-	if err != nil {
-		return model.ImageInRepo{}, err
+	if !ValidSize(newWidth, newHeight) {
+		return model.ImageInRepo{}, ErrBadParameters
 	}
 
-	return model.ImageInRepo{
-		ID:            is.Img.ImageID,
-		UploadsPath:   is.Img.UploadsPath,
-		ProcessedPath: outFileName,
-		Processed:     true,
-	}, nil
+	return transform(is, "resized", func(img image.Image) (image.Image, error) {
+		return scaleAndSharpen(img, newWidth, newHeight, is.Img.Parameters)
+	})
 }
 
-func resizeGIF(is ImageService, gifData *gif.GIF) (model.ImageInRepo, error) {
-	if is.Img.Parameters.Height == nil || is.Img.Parameters.Width == nil {
-		return model.ImageInRepo{}, ErrBadParameters
+func scaleAndSharpen(img image.Image, width, height int, params model.ProcessingParams) (image.Image, error) {
+	sharpen := params.Sharpen
+	if sharpen != nil && (*sharpen <= 0 || *sharpen > maxSharpen) {
+		return nil, ErrBadParameters
 	}
 
-	newWidth := *is.Img.Parameters.Width
-	newHeight := *is.Img.Parameters.Height
-
-	resizedGIF := &gif.GIF{
-		Image:    make([]*image.Paletted, len(gifData.Image)),
-		Delay:    gifData.Delay,
-		Disposal: gifData.Disposal,
+	scaled, err := scale(img, width, height, params)
+	if err != nil {
+		return nil, err
 	}
-
-	for i, frame := range gifData.Image {
-		bounds := frame.Bounds()
-
-		rgbaFrame := image.NewRGBA(bounds)
-		draw.Draw(rgbaFrame, bounds, frame, bounds.Min, draw.Src)
-
-		resizedFrame := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
-		xdraw.BiLinear.Scale(resizedFrame, resizedFrame.Bounds(), rgbaFrame, bounds, draw.Over, nil)
-
-		palettedFrame := image.NewPaletted(resizedFrame.Bounds(), frame.Palette)
-		draw.Draw(palettedFrame, palettedFrame.Bounds(), resizedFrame, resizedFrame.Bounds().Min, draw.Src)
-
-		resizedGIF.Image[i] = palettedFrame
+	if sharpen == nil {
+		return scaled, nil
 	}
 
-	var outFileName string
-	switch is.Img.TypeProcessing {
-	case "resize":
-		outFileName = fmt.Sprintf("processed/resized/%s-resized.gif", uuid.New().String())
-	case "thumbnail":
-		outFileName = fmt.Sprintf("processed/thumbnails/%s-thumbnails.gif", uuid.New().String())
-	}
+	radius, sigma := blurRadius(params)
+	return unsharpMask(scaled, *sharpen, radius, sigma), nil
+}
+
+func scale(img image.Image, width, height int, params model.ProcessingParams) (*image.RGBA, error) {
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
 
-	err := saveGIF(is, outFileName, resizedGIF)
+	interp, err := interpolator(params, img.Bounds(), scaled.Bounds())
 	if err != nil {
-		return model.ImageInRepo{}, err
+		return nil, err
 	}
+	interp.Scale(scaled, scaled.Bounds(), img, img.Bounds(), draw.Over, nil)
 
-	return model.ImageInRepo{
-		ID:            is.Img.ImageID,
-		UploadsPath:   is.Img.UploadsPath,
-		ProcessedPath: outFileName,
-		Processed:     true,
-	}, nil
+	return scaled, nil
 }
 
 func watermark(is ImageService) (model.ImageInRepo, error) {
-	baseFile, err := is.ImageStorage.Download(is.Ctx, is.Img.UploadsPath)
-	if err != nil {
-		return model.ImageInRepo{}, err
-	}
-	defer func() {
-		if err := baseFile.Close(); err != nil {
-			zlog.Logger.Error().Msg(err.Error())
-		}
-	}()
-
-	baseImg, format, err := image.Decode(baseFile)
-	if err != nil {
-		return model.ImageInRepo{}, err
+	if is.Img.Parameters.WatermarkPath == nil {
+		return model.ImageInRepo{}, ErrBadParameters
 	}
 
-	overlayFile, err := is.ImageStorage.Download(is.Ctx, *is.Img.Parameters.WatermarkPath)
+	overlayData, err := download(is, *is.Img.Parameters.WatermarkPath)
 	if err != nil {
 		return model.ImageInRepo{}, err
 	}
-	defer func() {
-		if err := overlayFile.Close(); err != nil {
-			zlog.Logger.Error().Msg(err.Error())
-		}
-	}()
 
-	overlayImg, _, err := image.Decode(overlayFile)
+	overlayImg, _, err := image.Decode(bytes.NewReader(overlayData))
 	if err != nil {
 		return model.ImageInRepo{}, err
 	}
 
-	baseBounds := baseImg.Bounds()
-
-	newWidth := baseBounds.Dx()
-	newHeight := baseBounds.Dy()
-	resizedOverlay := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
-
-	xdraw.BiLinear.Scale(resizedOverlay, resizedOverlay.Bounds(), overlayImg, overlayImg.Bounds(), draw.Over, nil)
+	res, err := transform(is, "watermarked", func(img image.Image) (image.Image, error) {
+		baseBounds := img.Bounds()
 
-	output := image.NewRGBA(baseBounds)
-	draw.Draw(output, baseBounds, baseImg, image.Point{0, 0}, draw.Src)
-	draw.Draw(output, resizedOverlay.Bounds(), resizedOverlay, image.Point{}, draw.Over)
-
-	outFileName := fmt.Sprintf("processed/watermarked/%s-watermarked.%s", uuid.New().String(), format)
+		resizedOverlay, err := scale(overlayImg, baseBounds.Dx(), baseBounds.Dy(), is.Img.Parameters)
+		if err != nil {
+			return nil, err
+		}
 
-	err = saveImage(is, outFileName, output, format)
+		output := toRGBA(img)
+		draw.Draw(output, resizedOverlay.Bounds(), resizedOverlay, image.Point{}, draw.Over)
+		return output, nil
+	})
 	if err != nil {
 		return model.ImageInRepo{}, err
 	}
 
-	err = is.ImageStorage.Delete(is.Ctx, *is.Img.Parameters.WatermarkPath)
-	if err != nil {
-		zlog.Logger.Error().Msg(err.Error())
+	if ownsWatermark(is.Img.Tenant, *is.Img.Parameters.WatermarkPath) {
+		err = is.ImageStorage.Delete(is.Ctx, *is.Img.Parameters.WatermarkPath)
+		if err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
 	}
 
-	return model.ImageInRepo{
-		ID:            is.Img.ImageID,
-		UploadsPath:   is.Img.UploadsPath,
-		ProcessedPath: outFileName,
-		Processed:     true,
-	}, nil
+	return res, nil
 }
 
 func saveImage(is ImageService, outFilename string, img image.Image, format string) error {
-	outFile := &bytes.Buffer{}
-	fileWriter := bufio.NewWriter(outFile)
-
-	var err error
-	switch format {
-	case "jpeg":
-		err = jpeg.Encode(fileWriter, img, &jpeg.Options{Quality: 90})
-	case "gif":
-		err = gif.Encode(fileWriter, img, nil)
-	case "png":
-		err = png.Encode(fileWriter, img)
-	default:
-		err = png.Encode(fileWriter, img)
-	}
-
-	if err != nil {
-		return err
-	}
-
-	fileReader := bufio.NewReader(outFile)
-	err = is.ImageStorage.Upload(is.Ctx, fileReader, outFilename, int64(outFile.Len()))
-	if err != nil {
-		return err
-	}
-	return nil
+	quality := defaultQuality
+	if is.Img.Parameters.Quality != nil {
+		quality = *is.Img.Parameters.Quality
+	}
+
+	return upload(is, outFilename, func(w io.Writer) error {
+		switch format {
+		case "jpeg":
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+		case "gif":
+			return gif.Encode(w, img, nil)
+		default:
+			return png.Encode(w, img)
+		}
+	})
 }
 
 func saveGIF(is ImageService, outFilename string, gifData *gif.GIF) error {
-	outFile := &bytes.Buffer{}
+	return upload(is, outFilename, func(w io.Writer) error {
+		return gif.EncodeAll(w, gifData)
+	})
+}
 
-	err := gif.EncodeAll(outFile, gifData)
-	if err != nil {
-		return err
-	}
+// upload pipes the output of encode into the ImageStore while it is being
+// encoded. The size is unknown in advance, so the store uploads it in parts.
+func upload(is ImageService, objectName string, encode func(io.Writer) error) error {
+	pr, pw := io.Pipe()
+
+	encodeErr := make(chan error, 1)
+	go func() {
+		w := bufio.NewWriter(pw)
+		err := encode(w)
+		if err == nil {
+			err = w.Flush()
+		}
+		pw.CloseWithError(err)
+		encodeErr <- err
+	}()
 
-	fileReader := bufio.NewReader(outFile)
-	err = is.ImageStorage.Upload(is.Ctx, fileReader, outFilename, int64(outFile.Len()))
-	if err != nil {
-		return err
+	err := is.ImageStorage.Upload(is.Ctx, pr, objectName, -1)
+	// Unblocks the encoder if the store stopped reading early.
+	pr.CloseWithError(err)
+
+	if encErr := <-encodeErr; encErr != nil && (err == nil || !errors.Is(encErr, io.ErrClosedPipe)) {
+		return encErr
 	}
-	return nil
+	return err
 }
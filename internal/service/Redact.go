@@ -0,0 +1,141 @@
+package service
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"ImageProcessor/internal/model"
+)
+
+const (
+	RedactPixelate = "pixelate"
+	RedactBlur     = "blur"
+	RedactFill     = "fill"
+)
+
+const (
+	defaultBlockSize    = 16
+	defaultRedactRadius = 10
+	defaultRedactColor  = "#000000"
+)
+
+func ValidateRedactions(redactions []model.Redaction) error {
+	if len(redactions) == 0 {
+		return ErrBadParameters
+	}
+
+	for _, r := range redactions {
+		if r.Width <= 0 || r.Height <= 0 || r.X < 0 || r.Y < 0 {
+			return ErrBadParameters
+		}
+
+		switch r.Method {
+		case RedactPixelate:
+			if r.BlockSize < 0 {
+				return ErrBadParameters
+			}
+		case RedactBlur:
+			if r.Radius < 0 || r.Radius > maxBlurRadius {
+				return ErrBadParameters
+			}
+		case RedactFill:
+			if r.Color != "" {
+				if _, err := ParseColor(r.Color); err != nil {
+					return err
+				}
+			}
+		default:
+			return ErrBadParameters
+		}
+	}
+	return nil
+}
+
+func redact(is ImageService) (model.ImageInRepo, error) {
+	redactions := is.Img.Parameters.Redactions
+	if err := ValidateRedactions(redactions); err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	res, err := transform(is, "redacted", func(img image.Image) (image.Image, error) {
+		out := toRGBA(img)
+		for _, r := range redactions {
+			if err := applyRedaction(out, r); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	})
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	res.Result = &model.ProcessingResult{Redactions: redactions}
+	return res, nil
+}
+
+func applyRedaction(img *image.RGBA, r model.Redaction) error {
+	rect := image.Rect(r.X, r.Y, r.X+r.Width, r.Y+r.Height).Intersect(img.Bounds())
+	if rect.Empty() {
+		return nil
+	}
+
+	switch r.Method {
+	case RedactPixelate:
+		blockSize := r.BlockSize
+		if blockSize == 0 {
+			blockSize = defaultBlockSize
+		}
+		pixelate(img, rect, blockSize)
+
+	case RedactBlur:
+		radius := r.Radius
+		if radius == 0 {
+			radius = defaultRedactRadius
+		}
+		region := toRGBA(img.SubImage(rect))
+		blurred := gaussianBlur(region, radius, float64(radius)/2)
+		draw.Draw(img, rect, blurred, image.Point{}, draw.Src)
+
+	case RedactFill:
+		fillColor := r.Color
+		if fillColor == "" {
+			fillColor = defaultRedactColor
+		}
+		c, err := ParseColor(fillColor)
+		if err != nil {
+			return err
+		}
+		draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	return nil
+}
+
+// pixelate replaces every block inside rect with its average color.
+func pixelate(img *image.RGBA, rect image.Rectangle, blockSize int) {
+	for by := rect.Min.Y; by < rect.Max.Y; by += blockSize {
+		for bx := rect.Min.X; bx < rect.Max.X; bx += blockSize {
+			block := image.Rect(bx, by, bx+blockSize, by+blockSize).Intersect(rect)
+
+			var sum [4]int
+			for y := block.Min.Y; y < block.Max.Y; y++ {
+				for x := block.Min.X; x < block.Max.X; x++ {
+					i := img.PixOffset(x, y)
+					for c := 0; c < 4; c++ {
+						sum[c] += int(img.Pix[i+c])
+					}
+				}
+			}
+
+			n := block.Dx() * block.Dy()
+			avg := color.RGBA{
+				R: uint8(sum[0] / n),
+				G: uint8(sum[1] / n),
+				B: uint8(sum[2] / n),
+				A: uint8(sum[3] / n),
+			}
+			draw.Draw(img, block, image.NewUniform(avg), image.Point{}, draw.Src)
+		}
+	}
+}
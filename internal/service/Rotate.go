@@ -0,0 +1,174 @@
+package service
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/math/f64"
+
+	"ImageProcessor/internal/model"
+)
+
+const (
+	FlipHorizontal = "horizontal"
+	FlipVertical   = "vertical"
+	FlipBoth       = "both"
+)
+
+const defaultBackground = "#ffffff"
+
+func ValidFlip(flip string) bool {
+	return flip == FlipHorizontal || flip == FlipVertical || flip == FlipBoth
+}
+
+func rotate(is ImageService) (model.ImageInRepo, error) {
+	params := is.Img.Parameters
+	if (params.Angle == nil && params.Flip == nil) || !finiteParams(params) {
+		return model.ImageInRepo{}, ErrBadParameters
+	}
+
+	var angle float64
+	if params.Angle != nil {
+		angle = math.Mod(*params.Angle, 360)
+		if angle < 0 {
+			angle += 360
+		}
+	}
+
+	var flip string
+	if params.Flip != nil {
+		flip = *params.Flip
+		if !ValidFlip(flip) {
+			return model.ImageInRepo{}, ErrBadParameters
+		}
+	}
+
+	background := defaultBackground
+	if params.Background != nil {
+		background = *params.Background
+	}
+	bg, err := ParseColor(background)
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	expand := params.Expand != nil && *params.Expand
+
+	return transform(is, "rotated", func(img image.Image) (image.Image, error) {
+		var out *image.RGBA
+		switch angle {
+		case 0:
+			out = toRGBA(img)
+		case 90, 180, 270:
+			out = rotateRight(img, int(angle)/90)
+		default:
+			src := toRGBA(img)
+			out = image.NewRGBA(rotatedBounds(src.Bounds(), angle, expand))
+			draw.Draw(out, out.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+
+			interp, err := interpolator(params, src.Bounds(), out.Bounds())
+			if err != nil {
+				return nil, err
+			}
+			interp.Transform(out, rotationMatrix(src.Bounds(), out.Bounds(), angle), src, src.Bounds(), draw.Over, nil)
+		}
+
+		switch flip {
+		case FlipHorizontal:
+			flipImage(out, true, false)
+		case FlipVertical:
+			flipImage(out, false, true)
+		case FlipBoth:
+			flipImage(out, true, true)
+		}
+		return out, nil
+	})
+}
+
+// rotateRight turns the image clockwise by quarter turns without resampling.
+func rotateRight(img image.Image, quarters int) *image.RGBA {
+	src := toRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+
+	var out *image.RGBA
+	if quarters%2 == 1 {
+		out = image.NewRGBA(image.Rect(0, 0, h, w))
+	} else {
+		out = image.NewRGBA(image.Rect(0, 0, w, h))
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch quarters {
+			case 1:
+				dx, dy = h-1-y, x
+			case 2:
+				dx, dy = w-1-x, h-1-y
+			case 3:
+				dx, dy = y, w-1-x
+			}
+			copy(out.Pix[out.PixOffset(dx, dy):out.PixOffset(dx, dy)+4], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+		}
+	}
+	return out
+}
+
+func flipImage(img *image.RGBA, horizontal, vertical bool) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	if horizontal {
+		for y := 0; y < h; y++ {
+			for x := 0; x < w/2; x++ {
+				swapPixels(img, b.Min.X+x, b.Min.Y+y, b.Min.X+w-1-x, b.Min.Y+y)
+			}
+		}
+	}
+	if vertical {
+		for y := 0; y < h/2; y++ {
+			for x := 0; x < w; x++ {
+				swapPixels(img, b.Min.X+x, b.Min.Y+y, b.Min.X+x, b.Min.Y+h-1-y)
+			}
+		}
+	}
+}
+
+func swapPixels(img *image.RGBA, x1, y1, x2, y2 int) {
+	i, j := img.PixOffset(x1, y1), img.PixOffset(x2, y2)
+	for k := 0; k < 4; k++ {
+		img.Pix[i+k], img.Pix[j+k] = img.Pix[j+k], img.Pix[i+k]
+	}
+}
+
+// rotatedBounds returns the output canvas: either the original size or the
+// bounding box of the rotated image when expand is set.
+func rotatedBounds(src image.Rectangle, angle float64, expand bool) image.Rectangle {
+	if !expand {
+		return image.Rect(0, 0, src.Dx(), src.Dy())
+	}
+
+	rad := angle * math.Pi / 180
+	sin, cos := math.Abs(math.Sin(rad)), math.Abs(math.Cos(rad))
+	w := float64(src.Dx())*cos + float64(src.Dy())*sin
+	h := float64(src.Dx())*sin + float64(src.Dy())*cos
+	return image.Rect(0, 0, int(math.Ceil(w-1e-9)), int(math.Ceil(h-1e-9)))
+}
+
+// rotationMatrix maps source pixels to destination pixels rotating clockwise
+// around the centers of both rectangles.
+func rotationMatrix(src, dst image.Rectangle, angle float64) f64.Aff3 {
+	rad := angle * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+
+	scx := float64(src.Min.X) + float64(src.Dx())/2
+	scy := float64(src.Min.Y) + float64(src.Dy())/2
+	dcx := float64(dst.Min.X) + float64(dst.Dx())/2
+	dcy := float64(dst.Min.Y) + float64(dst.Dy())/2
+
+	return f64.Aff3{
+		cos, -sin, dcx - (cos*scx - sin*scy),
+		sin, cos, dcy - (sin*scx + cos*scy),
+	}
+}
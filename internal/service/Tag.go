@@ -0,0 +1,19 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var tagPattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N}_-]{0,63}$`)
+
+// NormalizeTag lower-cases a tag and checks that it consists of letters,
+// digits, '-' and '_' with at most 64 characters.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if !tagPattern.MatchString(tag) {
+		return "", fmt.Errorf("invalid tag %q", tag)
+	}
+	return tag, nil
+}
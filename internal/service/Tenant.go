@@ -0,0 +1,55 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+)
+
+// Tenants is the immutable configuration of the tenants. Tenants without a
+// configuration may upload every format and use the default preset.
+type Tenants struct {
+	byID map[string]model.TenantConfig
+}
+
+// NewTenants validates the tenant ids, their formats and default presets.
+func NewTenants(configs []model.TenantConfig, presets *Presets) (*Tenants, error) {
+	byID := make(map[string]model.TenantConfig, len(configs))
+	for _, cfg := range configs {
+		if !repository.ValidTenant(cfg.ID) {
+			return nil, fmt.Errorf("invalid tenant id %q", cfg.ID)
+		}
+		if _, ok := byID[cfg.ID]; ok {
+			return nil, fmt.Errorf("duplicate tenant %s", cfg.ID)
+		}
+		for _, format := range cfg.Formats {
+			if !ValidFormat(format) {
+				return nil, fmt.Errorf("tenant %s: unsupported format %q", cfg.ID, format)
+			}
+		}
+		if cfg.DefaultPreset != "" {
+			if _, err := presets.Get(cfg.DefaultPreset); err != nil {
+				return nil, fmt.Errorf("tenant %s: default preset %s: %w", cfg.ID, cfg.DefaultPreset, err)
+			}
+		}
+		byID[cfg.ID] = cfg
+	}
+	return &Tenants{byID: byID}, nil
+}
+
+// Get returns the configuration of the tenant, an empty DefaultPreset means
+// the default preset of the service.
+func (t *Tenants) Get(id string) model.TenantConfig {
+	cfg, ok := t.byID[id]
+	if !ok {
+		return model.TenantConfig{ID: id}
+	}
+	return cfg
+}
+
+func (t *Tenants) AllowsFormat(id, format string) bool {
+	formats := t.Get(id).Formats
+	return len(formats) == 0 || slices.Contains(formats, format)
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+
+	"github.com/google/uuid"
+	"github.com/wb-go/wbf/zlog"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository"
+)
+
+type frameFunc func(img image.Image) (image.Image, error)
+
+// transform applies fn to a still image or to every frame of an animated GIF
+// and stores the result as processed/<name>/<uuid>-<name>.<format> of the
+// tenant.
+func transform(is ImageService, name string, fn frameFunc) (model.ImageInRepo, error) {
+	return process(is, name, fn, func(gifData *gif.GIF) (*gif.GIF, error) {
+		return transformGIF(gifData, fn)
+	})
+}
+
+func process(is ImageService, name string, stillFn frameFunc, gifFn func(*gif.GIF) (*gif.GIF, error)) (model.ImageInRepo, error) {
+	if err := ValidateOutput(is.Img.Parameters); err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	data, err := download(is, is.Img.UploadsPath)
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	var outFileName string
+
+	gifData, err := gif.DecodeAll(bytes.NewReader(data))
+	if err == nil {
+		outGIF, err := gifFn(gifData)
+		if err != nil {
+			return model.ImageInRepo{}, err
+		}
+
+		outFileName = outputName(is, name, "gif")
+		err = saveGIF(is, outFileName, outGIF)
+		if err != nil {
+			return model.ImageInRepo{}, err
+		}
+	} else {
+		baseImg, format, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return model.ImageInRepo{}, err
+		}
+
+		outImg, err := stillFn(baseImg)
+		if err != nil {
+			return model.ImageInRepo{}, err
+		}
+
+		format, outImg = outputFormat(is.Img.Parameters, format, outImg)
+
+		outFileName = outputName(is, name, format)
+		err = saveImage(is, outFileName, outImg, format)
+		if err != nil {
+			return model.ImageInRepo{}, err
+		}
+	}
+
+	return model.ImageInRepo{
+		ID:            is.Img.ImageID,
+		UploadsPath:   is.Img.UploadsPath,
+		ProcessedPath: outFileName,
+		Processed:     true,
+	}, nil
+}
+
+func outputName(is ImageService, name, format string) string {
+	if is.OutputName != "" {
+		return is.OutputName
+	}
+	return repository.TenantKey(is.Img.Tenant, fmt.Sprintf("processed/%s/%s-%s.%s", name, uuid.New().String(), name, format))
+}
+
+// ValidateOutput checks the requested output format and JPEG quality.
+func ValidateOutput(params model.ProcessingParams) error {
+	if params.Format != nil && !ValidFormat(*params.Format) {
+		return ErrBadParameters
+	}
+	if params.Quality != nil && (*params.Quality < 1 || *params.Quality > 100) {
+		return ErrBadParameters
+	}
+	return nil
+}
+
+// outputFormat picks the format of a still result. Without an explicit format
+// the source format is kept and JPEG with transparency is saved as PNG, an
+// explicit JPEG is flattened onto the default background instead. Animated
+// GIFs always stay GIF.
+func outputFormat(params model.ProcessingParams, format string, img image.Image) (string, image.Image) {
+	if params.Format == nil {
+		if format == FormatJPEG && !isOpaque(img) {
+			return FormatPNG, img
+		}
+		return format, img
+	}
+
+	format = *params.Format
+	if format == FormatJPEG && !isOpaque(img) {
+		bg, _ := ParseColor(defaultBackground)
+		out := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+		draw.Draw(out, out.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+		draw.Draw(out, out.Bounds(), img, img.Bounds().Min, draw.Over)
+		return format, out
+	}
+	return format, img
+}
+
+func download(is ImageService, objectName string) ([]byte, error) {
+	file, err := is.ImageStorage.Download(is.Ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(file)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func transformGIF(gifData *gif.GIF, fn frameFunc) (*gif.GIF, error) {
+	frames := coalesceGIF(gifData)
+
+	outGIF := &gif.GIF{
+		Image:     make([]*image.Paletted, len(frames)),
+		Delay:     gifData.Delay,
+		Disposal:  make([]byte, len(frames)),
+		LoopCount: gifData.LoopCount,
+	}
+
+	for i, frame := range frames {
+		outFrame, err := fn(frame)
+		if err != nil {
+			return nil, err
+		}
+
+		palette := gifData.Image[i].Palette
+		if !isOpaque(outFrame) {
+			palette = withTransparent(palette)
+		}
+		outGIF.Image[i] = toPaletted(outFrame, palette)
+		outGIF.Disposal[i] = gif.DisposalBackground
+	}
+
+	return outGIF, nil
+}
+
+// coalesceGIF renders every frame onto the full logical screen, so operations
+// see complete pictures instead of partial frames with offsets.
+func coalesceGIF(gifData *gif.GIF) []*image.RGBA {
+	bounds := image.Rect(0, 0, gifData.Config.Width, gifData.Config.Height)
+	for _, frame := range gifData.Image {
+		bounds = bounds.Union(frame.Bounds())
+	}
+
+	canvas := image.NewRGBA(bounds)
+	frames := make([]*image.RGBA, len(gifData.Image))
+
+	for i, frame := range gifData.Image {
+		var disposal byte
+		if i < len(gifData.Disposal) {
+			disposal = gifData.Disposal[i]
+		}
+
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = cloneRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		frames[i] = cloneRGBA(canvas)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return frames
+}
+
+// isOpaque reports whether img has no transparent pixels, JPEG output is
+// replaced with PNG for images that do.
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return true
+}
+
+// withTransparent returns the palette with a fully transparent entry,
+// replacing the last color when the palette is already full.
+func withTransparent(palette color.Palette) color.Palette {
+	for _, c := range palette {
+		if _, _, _, a := c.RGBA(); a == 0 {
+			return palette
+		}
+	}
+
+	out := make(color.Palette, len(palette), len(palette)+1)
+	copy(out, palette)
+	if len(out) >= 256 {
+		out[len(out)-1] = color.Transparent
+		return out
+	}
+	return append(out, color.Transparent)
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	clone := image.NewRGBA(img.Bounds())
+	copy(clone.Pix, img.Pix)
+	return clone
+}
+
+func toPaletted(img image.Image, palette color.Palette) *image.Paletted {
+	bounds := img.Bounds()
+	paletted := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), palette)
+	draw.Draw(paletted, paletted.Bounds(), img, bounds.Min, draw.Src)
+	return paletted
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ImageProcessor/internal/model"
+)
+
+// ModeCrop is accepted in transformation URLs next to the resize modes and
+// cuts the window without scaling.
+const ModeCrop = "crop"
+
+var (
+	ErrBadSignature = fmt.Errorf("bad signature")
+	ErrNotProcessed = fmt.Errorf("image is not processed yet")
+)
+
+// URLOptions are the operations requested by a transformation URL, written as
+// comma separated key_value pairs: w_400,h_300,m_fill,g_smart,f_jpeg,q_80.
+type URLOptions struct {
+	Width   int
+	Height  int
+	Mode    string
+	Gravity string
+	Format  string
+	Quality int
+}
+
+func ParseURLOptions(options string) (URLOptions, error) {
+	opts := URLOptions{Mode: ResizeFit}
+
+	for _, part := range strings.Split(options, ",") {
+		key, value, ok := strings.Cut(part, "_")
+		if !ok || value == "" {
+			return URLOptions{}, fmt.Errorf("invalid option %q", part)
+		}
+
+		var err error
+		switch key {
+		case "w":
+			opts.Width, err = strconv.Atoi(value)
+		case "h":
+			opts.Height, err = strconv.Atoi(value)
+		case "m":
+			opts.Mode = value
+		case "g":
+			opts.Gravity = value
+		case "f":
+			opts.Format = value
+		case "q":
+			opts.Quality, err = strconv.Atoi(value)
+			if err == nil && opts.Quality == 0 {
+				return URLOptions{}, fmt.Errorf("quality must be between 1 and 100")
+			}
+		default:
+			return URLOptions{}, fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return URLOptions{}, fmt.Errorf("invalid option %q", part)
+		}
+	}
+
+	if err := opts.validate(); err != nil {
+		return URLOptions{}, err
+	}
+	return opts, nil
+}
+
+func (o URLOptions) validate() error {
+	if o.Width < 0 || o.Height < 0 || o.Width > MaxImageSize || o.Height > MaxImageSize {
+		return ErrBadParameters
+	}
+	if o.Width == 0 && o.Height == 0 {
+		return fmt.Errorf("width or height required")
+	}
+	if (o.Width == 0 || o.Height == 0) && o.Mode != ResizeFit {
+		return fmt.Errorf("mode %s requires width and height", o.Mode)
+	}
+	if o.Mode != ModeCrop && !ValidResizeMode(o.Mode) {
+		return fmt.Errorf("unsupported mode %q", o.Mode)
+	}
+	if o.Gravity != "" && !ValidGravity(o.Gravity) {
+		return fmt.Errorf("unsupported gravity %q", o.Gravity)
+	}
+	if o.Format != "" && !ValidFormat(o.Format) {
+		return fmt.Errorf("unsupported format %q", o.Format)
+	}
+	if o.Quality < 0 || o.Quality > 100 {
+		return fmt.Errorf("quality must be between 1 and 100")
+	}
+	return nil
+}
+
+// String returns the options in canonical order, equal options give equal
+// strings regardless of how they were written in the URL.
+func (o URLOptions) String() string {
+	var parts []string
+	if o.Width != 0 {
+		parts = append(parts, "w_"+strconv.Itoa(o.Width))
+	}
+	if o.Height != 0 {
+		parts = append(parts, "h_"+strconv.Itoa(o.Height))
+	}
+	parts = append(parts, "m_"+o.Mode)
+	if o.Gravity != "" {
+		parts = append(parts, "g_"+o.Gravity)
+	}
+	if o.Format != "" {
+		parts = append(parts, "f_"+o.Format)
+	}
+	if o.Quality != 0 {
+		parts = append(parts, "q_"+strconv.Itoa(o.Quality))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Task builds the processing task for img. Variants of redacted images are
+// built from the processed image so they do not reveal the redacted areas,
+// other images use the original. Until an image is processed it is not known
+// whether it is redacted, so it has no variants yet.
+func (o URLOptions) Task(img model.ImageInRepo) (model.ImageTask, error) {
+	if !img.Processed {
+		return model.ImageTask{}, ErrNotProcessed
+	}
+	source := img.UploadsPath
+	if img.Result != nil && len(img.Result.Redactions) > 0 {
+		source = img.ProcessedPath
+	}
+
+	width, height := o.Width, o.Height
+	if width == 0 {
+		width = MaxImageSize
+	}
+	if height == 0 {
+		height = MaxImageSize
+	}
+
+	task := model.ImageTask{
+		ImageID:        img.ID,
+		Tenant:         img.Tenant,
+		TypeProcessing: "thumbnail",
+		UploadsPath:    source,
+		Parameters: model.ProcessingParams{
+			Width:  &width,
+			Height: &height,
+		},
+	}
+
+	if o.Mode == ModeCrop {
+		task.TypeProcessing = "crop"
+	} else {
+		mode := o.Mode
+		task.Parameters.ResizeMode = &mode
+	}
+	if o.Gravity != "" {
+		gravity := o.Gravity
+		task.Parameters.Gravity = &gravity
+	}
+	if o.Format != "" {
+		format := o.Format
+		task.Parameters.Format = &format
+	}
+	if o.Quality != 0 {
+		quality := o.Quality
+		task.Parameters.Quality = &quality
+	}
+	return task, nil
+}
+
+// VariantName is the object name of the cached variant. It has no extension
+// because animated GIFs stay GIF whatever format is requested.
+func (o URLOptions) VariantName(imageID int) string {
+	return fmt.Sprintf("processed/t/%d/%s", imageID, o.String())
+}
+
+// SignURL returns the URL safe base64 HMAC-SHA256 of "<options>/<image_id>",
+// images of other tenants than the default one sign
+// "<tenant>/<options>/<image_id>".
+func SignURL(secret, tenant, options string, imageID int) string {
+	message := fmt.Sprintf("%s/%d", options, imageID)
+	if tenant != "" && tenant != model.DefaultTenant {
+		message = tenant + "/" + message
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyURL(secret, signature, tenant, options string, imageID int) error {
+	if secret == "" {
+		return ErrBadSignature
+	}
+	expected := SignURL(secret, tenant, options, imageID)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrBadSignature
+	}
+	return nil
+}
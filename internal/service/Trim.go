@@ -0,0 +1,126 @@
+package service
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+
+	"ImageProcessor/internal/model"
+)
+
+const maxTolerance = 255
+
+func ValidateTrim(params model.ProcessingParams) error {
+	if params.Tolerance != nil && (*params.Tolerance < 0 || *params.Tolerance > maxTolerance) {
+		return ErrBadParameters
+	}
+	if params.Padding != nil && *params.Padding < 0 {
+		return ErrBadParameters
+	}
+	if params.TrimColor != nil {
+		if _, err := ParseColor(*params.TrimColor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trim crops uniform margins. The border color is the explicit trim color or
+// the top-left pixel, pixels whose channels all differ from it by no more than
+// tolerance count as border. GIF frames share one box so the animation keeps
+// a single size.
+func trim(is ImageService) (model.ImageInRepo, error) {
+	params := is.Img.Parameters
+	if err := ValidateTrim(params); err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	var tolerance, padding int
+	if params.Tolerance != nil {
+		tolerance = *params.Tolerance
+	}
+	if params.Padding != nil {
+		padding = *params.Padding
+	}
+
+	var box image.Rectangle
+
+	res, err := process(is, "trimmed", func(img image.Image) (image.Image, error) {
+		src := toRGBA(img)
+		box = padRect(contentBox(src, borderColor(src, params), tolerance), padding, src.Bounds())
+		return toRGBA(src.SubImage(box)), nil
+	}, func(gifData *gif.GIF) (*gif.GIF, error) {
+		frames := coalesceGIF(gifData)
+		for _, frame := range frames {
+			box = box.Union(contentBox(frame, borderColor(frame, params), tolerance))
+		}
+		box = padRect(box, padding, frames[0].Bounds())
+
+		return transformGIF(gifData, func(img image.Image) (image.Image, error) {
+			return toRGBA(toRGBA(img).SubImage(box)), nil
+		})
+	})
+	if err != nil {
+		return model.ImageInRepo{}, err
+	}
+
+	res.Result = &model.ProcessingResult{
+		TrimBox: &model.Rect{X: box.Min.X, Y: box.Min.Y, Width: box.Dx(), Height: box.Dy()},
+	}
+	return res, nil
+}
+
+func borderColor(img *image.RGBA, params model.ProcessingParams) color.NRGBA {
+	if params.TrimColor != nil {
+		c, _ := ParseColor(*params.TrimColor)
+		return c
+	}
+	b := img.Bounds()
+	return color.NRGBAModel.Convert(img.At(b.Min.X, b.Min.Y)).(color.NRGBA)
+}
+
+// contentBox returns the smallest rectangle holding every non-border pixel,
+// or the whole image when there is nothing but border.
+func contentBox(img *image.RGBA, border color.NRGBA, tolerance int) image.Rectangle {
+	b := img.Bounds()
+	box := image.Rectangle{}
+	found := false
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.RGBAAt(x, y)).(color.NRGBA)
+			if colorDistance(c, border) <= tolerance {
+				continue
+			}
+			if !found {
+				box = image.Rect(x, y, x+1, y+1)
+				found = true
+				continue
+			}
+			box = box.Union(image.Rect(x, y, x+1, y+1))
+		}
+	}
+
+	if !found {
+		return b
+	}
+	return box
+}
+
+func colorDistance(a, b color.NRGBA) int {
+	d := absInt(int(a.R) - int(b.R))
+	d = max(d, absInt(int(a.G)-int(b.G)))
+	d = max(d, absInt(int(a.B)-int(b.B)))
+	return max(d, absInt(int(a.A)-int(b.A)))
+}
+
+func padRect(r image.Rectangle, padding int, bounds image.Rectangle) image.Rectangle {
+	return image.Rect(r.Min.X-padding, r.Min.Y-padding, r.Max.X+padding, r.Max.Y+padding).Intersect(bounds)
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
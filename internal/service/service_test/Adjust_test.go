@@ -0,0 +1,107 @@
+package servicetest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+)
+
+func TestAdjust(t *testing.T) {
+	yes := true
+	brighter := 0.2
+
+	tests := []struct {
+		name     string
+		params   model.ProcessingParams
+		input    color.NRGBA
+		expected color.NRGBA
+	}{
+		{
+			name:     "grayscale",
+			params:   model.ProcessingParams{Grayscale: &yes},
+			input:    color.NRGBA{255, 0, 0, 255},
+			expected: color.NRGBA{54, 54, 54, 255},
+		},
+		{
+			name:     "invert",
+			params:   model.ProcessingParams{Invert: &yes},
+			input:    color.NRGBA{10, 20, 30, 255},
+			expected: color.NRGBA{245, 235, 225, 255},
+		},
+		{
+			name:     "brightness keeps alpha",
+			params:   model.ProcessingParams{Brightness: &brighter},
+			input:    color.NRGBA{0, 100, 220, 128},
+			expected: color.NRGBA{51, 151, 255, 128},
+		},
+		{
+			name:     "grayscale and invert",
+			params:   model.ProcessingParams{Grayscale: &yes, Invert: &yes},
+			input:    color.NRGBA{255, 0, 0, 255},
+			expected: color.NRGBA{201, 201, 201, 255},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := model.ImageTask{
+				TypeProcessing: "adjust",
+				UploadsPath:    "uploads/test.png",
+				Parameters:     tt.params,
+			}
+
+			src := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+			for i := 0; i < 4; i++ {
+				src.SetNRGBA(i%2, i/2, tt.input)
+			}
+
+			res, output := processImage(t, task, encodePNG(t, src))
+			require.Contains(t, res.ProcessedPath, "processed/adjusted/")
+
+			img, err := png.Decode(bytes.NewReader(output))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, color.NRGBAModel.Convert(img.At(1, 1)))
+		})
+	}
+}
+
+func TestAdjustGIFPalette(t *testing.T) {
+	yes := true
+	task := model.ImageTask{
+		TypeProcessing: "adjust",
+		UploadsPath:    "uploads/test.gif",
+		Parameters:     model.ProcessingParams{Invert: &yes},
+	}
+
+	palette := color.Palette{color.RGBA{0, 0, 0, 255}, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 0, 0}}
+	anim := &gif.GIF{}
+	for i := 0; i < 2; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 2, 2), palette)
+		frame.SetColorIndex(i, 0, 1)
+		frame.SetColorIndex(1, 1, 2)
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 5)
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, gif.EncodeAll(buf, anim))
+
+	_, output := processImage(t, task, buf.Bytes())
+
+	out, err := gif.DecodeAll(bytes.NewReader(output))
+	require.NoError(t, err)
+	require.Len(t, out.Image, 2)
+	for i, frame := range out.Image {
+		require.Equal(t, uint8(1), frame.ColorIndexAt(i, 0))
+		require.Equal(t, color.RGBA{0, 255, 255, 255}, frame.Palette[1])
+		require.Equal(t, color.RGBA{255, 255, 255, 255}, frame.Palette[0])
+		_, _, _, a := frame.At(1, 1).RGBA()
+		require.Zero(t, a)
+	}
+}
@@ -0,0 +1,153 @@
+package servicetest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+)
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	buf := &bytes.Buffer{}
+	require.NoError(t, jpeg.Encode(buf, img, &jpeg.Options{Quality: 100}))
+	return buf.Bytes()
+}
+
+func TestPad(t *testing.T) {
+	padding := 2
+	background := "#ff0000"
+	task := model.ImageTask{
+		TypeProcessing: "pad",
+		UploadsPath:    "uploads/test.png",
+		Parameters:     model.ProcessingParams{Padding: &padding, Background: &background},
+	}
+
+	res, output := processImage(t, task, encodePNG(t, filled(4, 3, color.NRGBA{0, 0, 255, 255})))
+	require.Contains(t, res.ProcessedPath, "processed/padded/")
+
+	img, err := png.Decode(bytes.NewReader(output))
+	require.NoError(t, err)
+	require.Equal(t, image.Pt(8, 7), img.Bounds().Size())
+	require.Equal(t, color.NRGBA{255, 0, 0, 255}, color.NRGBAModel.Convert(img.At(0, 0)))
+	require.Equal(t, color.NRGBA{255, 0, 0, 255}, color.NRGBAModel.Convert(img.At(7, 6)))
+	require.Equal(t, color.NRGBA{0, 0, 255, 255}, color.NRGBAModel.Convert(img.At(2, 2)))
+	require.Equal(t, color.NRGBA{0, 0, 255, 255}, color.NRGBAModel.Convert(img.At(5, 4)))
+}
+
+func TestPadTransparentForcesPNG(t *testing.T) {
+	padding := 1
+	background := "#00000000"
+	task := model.ImageTask{
+		TypeProcessing: "pad",
+		UploadsPath:    "uploads/test.jpg",
+		Parameters:     model.ProcessingParams{Padding: &padding, Background: &background},
+	}
+
+	res, output := processImage(t, task, encodeJPEG(t, filled(4, 4, color.NRGBA{0, 0, 255, 255})))
+	require.True(t, strings.HasSuffix(res.ProcessedPath, ".png"))
+
+	img, err := png.Decode(bytes.NewReader(output))
+	require.NoError(t, err)
+	_, _, _, a := img.At(0, 0).RGBA()
+	require.Zero(t, a)
+}
+
+func TestBorder(t *testing.T) {
+	width := 1
+	task := model.ImageTask{
+		TypeProcessing: "border",
+		UploadsPath:    "uploads/test.jpg",
+		Parameters:     model.ProcessingParams{BorderWidth: &width},
+	}
+
+	res, output := processImage(t, task, encodeJPEG(t, filled(6, 6, color.NRGBA{255, 255, 255, 255})))
+	require.True(t, strings.HasSuffix(res.ProcessedPath, ".jpeg"))
+
+	img, err := jpeg.Decode(bytes.NewReader(output))
+	require.NoError(t, err)
+	require.Equal(t, image.Pt(6, 6), img.Bounds().Size())
+
+	edge, _, _, _ := img.At(0, 3).RGBA()
+	inner, _, _, _ := img.At(3, 3).RGBA()
+	require.Less(t, edge, uint32(0x2000))
+	require.Greater(t, inner, uint32(0xe000))
+}
+
+func TestRound(t *testing.T) {
+	radius := 4
+	yes := true
+
+	tests := []struct {
+		name         string
+		params       model.ProcessingParams
+		expectedSize image.Point
+	}{
+		{
+			name:         "rounded corners",
+			params:       model.ProcessingParams{CornerRadius: &radius},
+			expectedSize: image.Pt(10, 6),
+		},
+		{
+			name:         "circle",
+			params:       model.ProcessingParams{Circle: &yes},
+			expectedSize: image.Pt(6, 6),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := model.ImageTask{
+				TypeProcessing: "round",
+				UploadsPath:    "uploads/test.jpg",
+				Parameters:     tt.params,
+			}
+
+			res, output := processImage(t, task, encodeJPEG(t, filled(10, 6, color.NRGBA{0, 255, 0, 255})))
+			require.Contains(t, res.ProcessedPath, "processed/rounded/")
+			require.True(t, strings.HasSuffix(res.ProcessedPath, ".png"))
+
+			img, err := png.Decode(bytes.NewReader(output))
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSize, img.Bounds().Size())
+
+			_, _, _, corner := img.At(0, 0).RGBA()
+			_, _, _, center := img.At(3, 3).RGBA()
+			require.Zero(t, corner)
+			require.Equal(t, uint32(0xffff), center)
+		})
+	}
+}
+
+func TestRoundGIF(t *testing.T) {
+	yes := true
+	task := model.ImageTask{
+		TypeProcessing: "round",
+		UploadsPath:    "uploads/test.gif",
+		Parameters:     model.ProcessingParams{Circle: &yes},
+	}
+
+	palette := color.Palette{color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 255}}
+	anim := &gif.GIF{
+		Image: []*image.Paletted{image.NewPaletted(image.Rect(0, 0, 8, 8), palette)},
+		Delay: []int{0},
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, gif.EncodeAll(buf, anim))
+
+	_, output := processImage(t, task, buf.Bytes())
+
+	out, err := gif.DecodeAll(bytes.NewReader(output))
+	require.NoError(t, err)
+	_, _, _, corner := out.Image[0].At(0, 0).RGBA()
+	_, _, _, center := out.Image[0].At(4, 4).RGBA()
+	require.Zero(t, corner)
+	require.Equal(t, uint32(0xffff), center)
+}
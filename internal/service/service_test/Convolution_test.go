@@ -0,0 +1,138 @@
+package servicetest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/service"
+)
+
+func filled(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestFilter(t *testing.T) {
+	str := func(s string) *string { return &s }
+	gray := color.NRGBA{100, 100, 100, 255}
+
+	dot := filled(9, 9, color.NRGBA{0, 0, 0, 255})
+	dot.SetNRGBA(4, 4, color.NRGBA{255, 255, 255, 255})
+
+	tests := []struct {
+		name   string
+		params model.ProcessingParams
+		input  image.Image
+		check  func(t *testing.T, img image.Image)
+	}{
+		{
+			name:   "blur spreads a dot",
+			params: model.ProcessingParams{Filter: str(service.FilterBlur)},
+			input:  dot,
+			check: func(t *testing.T, img image.Image) {
+				center, _, _, _ := img.At(4, 4).RGBA()
+				near, _, _, _ := img.At(5, 4).RGBA()
+				far, _, _, _ := img.At(0, 0).RGBA()
+				require.Less(t, center, uint32(0xffff))
+				require.Greater(t, near, uint32(0))
+				require.Less(t, near, center)
+				require.Zero(t, far)
+			},
+		},
+		{
+			name:   "blur keeps large uniform image",
+			params: model.ProcessingParams{Filter: str(service.FilterBlur)},
+			input:  filled(300, 300, gray),
+			check: func(t *testing.T, img image.Image) {
+				for _, p := range []image.Point{{0, 0}, {150, 150}, {299, 299}, {10, 280}} {
+					require.Equal(t, gray, color.NRGBAModel.Convert(img.At(p.X, p.Y)))
+				}
+			},
+		},
+		{
+			name:   "edge of uniform image is black",
+			params: model.ProcessingParams{Filter: str(service.FilterEdge)},
+			input:  filled(5, 5, gray),
+			check: func(t *testing.T, img image.Image) {
+				require.Equal(t, color.NRGBA{0, 0, 0, 255}, color.NRGBAModel.Convert(img.At(2, 2)))
+			},
+		},
+		{
+			name: "custom identity kernel",
+			params: model.ProcessingParams{
+				Filter: str(service.FilterCustom),
+				Kernel: []float64{0, 0, 0, 0, 1, 0, 0, 0, 0},
+			},
+			input: dot,
+			check: func(t *testing.T, img image.Image) {
+				require.Equal(t, color.NRGBA{255, 255, 255, 255}, color.NRGBAModel.Convert(img.At(4, 4)))
+				require.Equal(t, color.NRGBA{0, 0, 0, 255}, color.NRGBAModel.Convert(img.At(3, 4)))
+			},
+		},
+		{
+			name:   "sharpen keeps uniform image",
+			params: model.ProcessingParams{Filter: str(service.FilterSharpen)},
+			input:  filled(9, 9, gray),
+			check: func(t *testing.T, img image.Image) {
+				require.Equal(t, gray, color.NRGBAModel.Convert(img.At(4, 4)))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := model.ImageTask{
+				TypeProcessing: "filter",
+				UploadsPath:    "uploads/test.png",
+				Parameters:     tt.params,
+			}
+
+			res, output := processImage(t, task, encodePNG(t, tt.input))
+			require.Contains(t, res.ProcessedPath, "processed/filtered/")
+
+			img, err := png.Decode(bytes.NewReader(output))
+			require.NoError(t, err)
+			require.Equal(t, tt.input.Bounds().Size(), img.Bounds().Size())
+			tt.check(t, img)
+		})
+	}
+}
+
+func TestValidateFilter(t *testing.T) {
+	str := func(s string) *string { return &s }
+	radius := 0
+
+	require.Error(t, service.ValidateFilter(model.ProcessingParams{}))
+	require.Error(t, service.ValidateFilter(model.ProcessingParams{Filter: str("median")}))
+	require.Error(t, service.ValidateFilter(model.ProcessingParams{Filter: str(service.FilterBlur), Radius: &radius}))
+	require.Error(t, service.ValidateFilter(model.ProcessingParams{Filter: str(service.FilterCustom), Kernel: []float64{1, 2, 3}}))
+	require.NoError(t, service.ValidateFilter(model.ProcessingParams{Filter: str(service.FilterCustom), Kernel: make([]float64, 25)}))
+}
+
+func TestResizeSharpen(t *testing.T) {
+	width, height := 4, 4
+	sharpen := 1.5
+	task := model.ImageTask{
+		TypeProcessing: "resize",
+		UploadsPath:    "uploads/test.png",
+		Parameters:     model.ProcessingParams{Width: &width, Height: &height, Sharpen: &sharpen},
+	}
+
+	_, output := processImage(t, task, encodePNG(t, filled(16, 16, color.NRGBA{10, 20, 30, 255})))
+
+	img, err := png.Decode(bytes.NewReader(output))
+	require.NoError(t, err)
+	require.Equal(t, image.Pt(4, 4), img.Bounds().Size())
+	require.Equal(t, color.NRGBA{10, 20, 30, 255}, color.NRGBAModel.Convert(img.At(1, 1)))
+}
@@ -0,0 +1,132 @@
+package servicetest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/service"
+)
+
+// detailed returns a white image with a checkerboard patch inside rect.
+func detailed(w, h int, rect image.Rectangle) *image.NRGBA {
+	img := filled(w, h, color.NRGBA{255, 255, 255, 255})
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if (x/2+y/2)%2 == 0 {
+				img.SetNRGBA(x, y, color.NRGBA{0, 0, 0, 255})
+			}
+		}
+	}
+	return img
+}
+
+func darkPixels(img image.Image) int {
+	var n int
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if r, _, _, _ := img.At(x, y).RGBA(); r < 0x8000 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestCrop(t *testing.T) {
+	str := func(s string) *string { return &s }
+	input := detailed(300, 100, image.Rect(220, 20, 280, 80))
+
+	tests := []struct {
+		name       string
+		gravity    *string
+		width      int
+		height     int
+		expectSize image.Point
+		hasDetail  bool
+	}{
+		{
+			name:       "center",
+			width:      100,
+			height:     100,
+			expectSize: image.Pt(100, 100),
+			hasDetail:  false,
+		},
+		{
+			name:       "east",
+			gravity:    str(service.GravityEast),
+			width:      100,
+			height:     100,
+			expectSize: image.Pt(100, 100),
+			hasDetail:  true,
+		},
+		{
+			name:       "smart",
+			gravity:    str(service.GravitySmart),
+			width:      100,
+			height:     100,
+			expectSize: image.Pt(100, 100),
+			hasDetail:  true,
+		},
+		{
+			name:       "size is clamped",
+			width:      500,
+			height:     50,
+			expectSize: image.Pt(300, 50),
+			hasDetail:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := model.ImageTask{
+				TypeProcessing: "crop",
+				UploadsPath:    "uploads/test.png",
+				Parameters: model.ProcessingParams{
+					Width:   &tt.width,
+					Height:  &tt.height,
+					Gravity: tt.gravity,
+				},
+			}
+
+			res, output := processImage(t, task, encodePNG(t, input))
+			require.Contains(t, res.ProcessedPath, "processed/cropped/")
+
+			img, err := png.Decode(bytes.NewReader(output))
+			require.NoError(t, err)
+			require.Equal(t, tt.expectSize, img.Bounds().Size())
+			require.Equal(t, tt.hasDetail, darkPixels(img) > 0)
+		})
+	}
+}
+
+func TestThumbnailSmartCrop(t *testing.T) {
+	size := 150
+	task := model.ImageTask{
+		TypeProcessing: "thumbnail",
+		UploadsPath:    "uploads/test.png",
+		Parameters: model.ProcessingParams{
+			Width:  &size,
+			Height: &size,
+		},
+	}
+
+	input := detailed(600, 300, image.Rect(20, 40, 200, 260))
+	res, output := processImage(t, task, encodePNG(t, input))
+	require.Contains(t, res.ProcessedPath, "processed/thumbnails/")
+
+	img, err := png.Decode(bytes.NewReader(output))
+	require.NoError(t, err)
+	require.Equal(t, image.Pt(size, size), img.Bounds().Size())
+
+	left := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}).SubImage(image.Rect(0, 0, 75, 150))
+	require.Greater(t, darkPixels(left), 1000)
+}
@@ -0,0 +1,154 @@
+package servicetest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+	"ImageProcessor/internal/service"
+)
+
+// memoryStore keeps uploaded objects in the map so consecutive steps can read
+// each other's results.
+func memoryStore(t *testing.T, objects map[string][]byte) *mocks.MockImageStore {
+	store := mocks.NewMockImageStore(t)
+	store.EXPECT().Download(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, name string) (io.ReadCloser, error) {
+		data, ok := objects[name]
+		if !ok {
+			return nil, fmt.Errorf("object %s not found", name)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}).Maybe()
+	store.EXPECT().Upload(mock.Anything, mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, file io.Reader, name string, _ int64) error {
+		data, err := io.ReadAll(file)
+		objects[name] = data
+		return err
+	}).Maybe()
+	store.EXPECT().Delete(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, name string) error {
+		delete(objects, name)
+		return nil
+	}).Maybe()
+	return store
+}
+
+func TestPipeline(t *testing.T) {
+	objects := map[string][]byte{
+		"uploads/test.png":         encodePNG(t, filled(200, 100, color.NRGBA{0, 0, 255, 255})),
+		"transformations/logo.png": encodePNG(t, filled(10, 10, color.NRGBA{0, 0, 0, 0})),
+	}
+
+	width, height, borderWidth := 40, 30, 2
+	borderColor := "#ff0000"
+	task := model.ImageTask{UploadsPath: "uploads/test.png"}
+	service.ApplyTransformation(&task, model.Transformation{
+		Name: "card",
+		Steps: []model.Step{
+			{TypeProcessing: "thumbnail", Parameters: model.ProcessingParams{Width: &width, Height: &height}},
+			{TypeProcessing: "watermark"},
+			{TypeProcessing: "border", Parameters: model.ProcessingParams{BorderWidth: &borderWidth, BorderColor: &borderColor}},
+		},
+		WatermarkPath: "transformations/logo.png",
+	})
+
+	res, err := service.ProcessImage(service.ImageService{
+		Ctx:          context.Background(),
+		ImageStorage: memoryStore(t, objects),
+		Img:          task,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "uploads/test.png", res.UploadsPath)
+	require.Contains(t, res.ProcessedPath, "processed/bordered/")
+
+	require.Len(t, objects, 3)
+	require.Contains(t, objects, "transformations/logo.png")
+
+	img, err := png.Decode(bytes.NewReader(objects[res.ProcessedPath]))
+	require.NoError(t, err)
+	require.Equal(t, image.Pt(40, 30), img.Bounds().Size())
+	require.Equal(t, color.NRGBA{255, 0, 0, 255}, color.NRGBAModel.Convert(img.At(0, 0)))
+	require.Equal(t, color.NRGBA{0, 0, 255, 255}, color.NRGBAModel.Convert(img.At(20, 15)))
+}
+
+func TestPipelineFailedStep(t *testing.T) {
+	objects := map[string][]byte{
+		"uploads/test.png": encodePNG(t, filled(20, 20, color.NRGBA{0, 0, 255, 255})),
+	}
+
+	width, height := 10, 10
+	task := model.ImageTask{
+		UploadsPath:    "uploads/test.png",
+		TypeProcessing: service.TypePipeline,
+		Steps: []model.Step{
+			{TypeProcessing: "resize", Parameters: model.ProcessingParams{Width: &width, Height: &height}},
+			{TypeProcessing: "unknown"},
+		},
+	}
+
+	_, err := service.ProcessImage(service.ImageService{
+		Ctx:          context.Background(),
+		ImageStorage: memoryStore(t, objects),
+		Img:          task,
+	})
+	require.ErrorIs(t, err, service.ErrUnknowMode)
+	require.Len(t, objects, 1)
+}
+
+func TestValidateStepNonFinite(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	expand := true
+	blur, custom := service.FilterBlur, service.FilterCustom
+
+	for name, step := range map[string]model.Step{
+		"rotate angle": {TypeProcessing: "rotate", Parameters: model.ProcessingParams{Angle: &inf, Expand: &expand}},
+		"adjust":       {TypeProcessing: "adjust", Parameters: model.ProcessingParams{Brightness: &nan}},
+		"filter sigma": {TypeProcessing: "filter", Parameters: model.ProcessingParams{Filter: &blur, Sigma: &nan}},
+		"kernel":       {TypeProcessing: "filter", Parameters: model.ProcessingParams{Filter: &custom, Kernel: []float64{1, 1, 1, 1, inf, 1, 1, 1, 1}}},
+	} {
+		require.ErrorIs(t, service.ValidateStep(step), service.ErrBadParameters, name)
+	}
+
+	require.ErrorIs(t, service.ValidateAdjust(model.ProcessingParams{Gamma: &nan}), service.ErrBadParameters)
+}
+
+func TestApplyTransformationWatermark(t *testing.T) {
+	foreign := "uploads/someone-else.png"
+	steps := []model.Step{
+		{TypeProcessing: "watermark", Parameters: model.ProcessingParams{WatermarkPath: &foreign}},
+		{TypeProcessing: "grayscale", Parameters: model.ProcessingParams{WatermarkPath: &foreign}},
+	}
+
+	var task model.ImageTask
+	service.ApplyTransformation(&task, model.Transformation{Name: "card", Steps: steps, WatermarkPath: "transformations/logo.png"})
+	require.Equal(t, "transformations/logo.png", *task.Steps[0].Parameters.WatermarkPath)
+	require.Nil(t, task.Steps[1].Parameters.WatermarkPath)
+	require.Equal(t, foreign, *steps[0].Parameters.WatermarkPath, "stored steps are not changed")
+
+	task = model.ImageTask{}
+	service.ApplyTransformation(&task, model.Transformation{Name: "card", Steps: steps})
+	require.Nil(t, task.Steps[0].Parameters.WatermarkPath)
+
+	err := service.ValidateTransformation(model.Transformation{Name: "card", Steps: steps[:1], WatermarkPath: "transformations/logo.png"})
+	require.Error(t, err)
+}
+
+func TestValidateStepSize(t *testing.T) {
+	huge, small := service.MaxImageSize+1, 10
+	for _, typeProcessing := range []string{"resize", "crop", "thumbnail"} {
+		step := model.Step{TypeProcessing: typeProcessing, Parameters: model.ProcessingParams{Width: &huge, Height: &small}}
+		require.ErrorIs(t, service.ValidateStep(step), service.ErrBadParameters, typeProcessing)
+
+		step.Parameters.Width = &small
+		require.NoError(t, service.ValidateStep(step), typeProcessing)
+	}
+}
@@ -0,0 +1,145 @@
+package servicetest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/service"
+)
+
+func TestNewPresets(t *testing.T) {
+	small := model.Preset{Name: "small", Width: 150, Height: 150, Mode: service.ResizeFill}
+	large := model.Preset{Name: "large", Width: 800, Height: 600, Mode: service.ResizeFit, Format: service.FormatPNG}
+
+	tests := []struct {
+		name        string
+		presets     []model.Preset
+		defaultName string
+		expectErr   bool
+	}{
+		{name: "valid", presets: []model.Preset{large, small}, defaultName: "small"},
+		{name: "first is default", presets: []model.Preset{large, small}},
+		{name: "empty", expectErr: true},
+		{name: "unknown default", presets: []model.Preset{small}, defaultName: "large", expectErr: true},
+		{name: "duplicate", presets: []model.Preset{small, small}, expectErr: true},
+		{
+			name:      "bad size",
+			presets:   []model.Preset{{Name: "zero", Height: 100, Mode: service.ResizeFill}},
+			expectErr: true,
+		},
+		{
+			name:      "bad mode",
+			presets:   []model.Preset{{Name: "crop", Width: 100, Height: 100, Mode: "crop"}},
+			expectErr: true,
+		},
+		{
+			name:      "bad format",
+			presets:   []model.Preset{{Name: "webp", Width: 100, Height: 100, Mode: service.ResizeFit, Format: "webp"}},
+			expectErr: true,
+		},
+		{
+			name:      "bad quality",
+			presets:   []model.Preset{{Name: "q", Width: 100, Height: 100, Mode: service.ResizeFit, Quality: 101}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			presets, err := service.NewPresets(tt.presets, tt.defaultName)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			preset, err := presets.Get("")
+			require.NoError(t, err)
+			require.Equal(t, presets.Default(), preset.Name)
+
+			_, err = presets.Get("missing")
+			require.ErrorIs(t, err, service.ErrUnknownPreset)
+
+			require.Equal(t, []model.Preset{small, large}, presets.List())
+		})
+	}
+}
+
+func TestThumbnailPreset(t *testing.T) {
+	tests := []struct {
+		name       string
+		preset     model.Preset
+		expectSize image.Point
+		expectExt  string
+	}{
+		{
+			name:       "fill",
+			preset:     model.Preset{Name: "fill", Width: 50, Height: 50, Mode: service.ResizeFill},
+			expectSize: image.Pt(50, 50),
+			expectExt:  ".png",
+		},
+		{
+			name:       "fit",
+			preset:     model.Preset{Name: "fit", Width: 50, Height: 50, Mode: service.ResizeFit},
+			expectSize: image.Pt(50, 25),
+			expectExt:  ".png",
+		},
+		{
+			name:       "stretch",
+			preset:     model.Preset{Name: "stretch", Width: 50, Height: 50, Mode: service.ResizeStretch},
+			expectSize: image.Pt(50, 50),
+			expectExt:  ".png",
+		},
+		{
+			name:       "jpeg output",
+			preset:     model.Preset{Name: "jpeg", Width: 40, Height: 20, Mode: service.ResizeFit, Format: service.FormatJPEG, Quality: 70},
+			expectSize: image.Pt(40, 20),
+			expectExt:  ".jpeg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := model.ImageTask{
+				TypeProcessing: "thumbnail",
+				UploadsPath:    "uploads/test.png",
+			}
+			service.ApplyPreset(&task.Parameters, tt.preset)
+
+			res, output := processImage(t, task, encodePNG(t, filled(200, 100, color.NRGBA{0, 0, 255, 255})))
+			require.True(t, strings.HasSuffix(res.ProcessedPath, tt.expectExt))
+
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(output))
+			require.NoError(t, err)
+			require.Equal(t, tt.expectSize, image.Pt(cfg.Width, cfg.Height))
+		})
+	}
+}
+
+func TestExplicitJPEGFlattensTransparency(t *testing.T) {
+	format := service.FormatJPEG
+	padding := 2
+	background := "#00000000"
+	task := model.ImageTask{
+		TypeProcessing: "pad",
+		UploadsPath:    "uploads/test.png",
+		Parameters:     model.ProcessingParams{Padding: &padding, Background: &background, Format: &format},
+	}
+
+	res, output := processImage(t, task, encodePNG(t, filled(4, 4, color.NRGBA{0, 0, 255, 255})))
+	require.True(t, strings.HasSuffix(res.ProcessedPath, ".jpeg"))
+
+	img, err := jpeg.Decode(bytes.NewReader(output))
+	require.NoError(t, err)
+	r, g, b, _ := img.At(0, 0).RGBA()
+	require.Greater(t, r>>8, uint32(240))
+	require.Greater(t, g>>8, uint32(240))
+	require.Greater(t, b>>8, uint32(240))
+}
@@ -0,0 +1,109 @@
+package servicetest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/service"
+)
+
+func checkerboard(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (x+y)%2 == 0 {
+				img.SetNRGBA(x, y, color.NRGBA{255, 255, 255, 255})
+			} else {
+				img.SetNRGBA(x, y, color.NRGBA{0, 0, 0, 255})
+			}
+		}
+	}
+	return img
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name      string
+		redaction model.Redaction
+		inside    color.NRGBA
+	}{
+		{
+			name:      "fill",
+			redaction: model.Redaction{X: 2, Y: 2, Width: 4, Height: 4, Method: service.RedactFill, Color: "#ff0000"},
+			inside:    color.NRGBA{255, 0, 0, 255},
+		},
+		{
+			name:      "pixelate",
+			redaction: model.Redaction{X: 2, Y: 2, Width: 4, Height: 4, Method: service.RedactPixelate, BlockSize: 2},
+			inside:    color.NRGBA{127, 127, 127, 255},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := model.ImageTask{
+				ImageID:        3,
+				TypeProcessing: "redact",
+				UploadsPath:    "uploads/test.png",
+				Parameters:     model.ProcessingParams{Redactions: []model.Redaction{tt.redaction}},
+			}
+
+			res, output := processImage(t, task, encodePNG(t, checkerboard(8, 8)))
+			require.Contains(t, res.ProcessedPath, "processed/redacted/")
+			require.NotNil(t, res.Result)
+			require.Equal(t, []model.Redaction{tt.redaction}, res.Result.Redactions)
+
+			img, err := png.Decode(bytes.NewReader(output))
+			require.NoError(t, err)
+			for _, p := range []image.Point{{2, 2}, {3, 2}, {5, 5}} {
+				require.Equal(t, tt.inside, color.NRGBAModel.Convert(img.At(p.X, p.Y)))
+			}
+			require.Equal(t, color.NRGBA{255, 255, 255, 255}, color.NRGBAModel.Convert(img.At(0, 0)))
+			require.Equal(t, color.NRGBA{0, 0, 0, 255}, color.NRGBAModel.Convert(img.At(7, 6)))
+		})
+	}
+}
+
+func TestRedactGIF(t *testing.T) {
+	task := model.ImageTask{
+		TypeProcessing: "redact",
+		UploadsPath:    "uploads/test.gif",
+		Parameters: model.ProcessingParams{Redactions: []model.Redaction{
+			{X: 0, Y: 0, Width: 4, Height: 4, Method: service.RedactFill},
+		}},
+	}
+
+	palette := color.Palette{color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 255}}
+	anim := &gif.GIF{}
+	for i := 0; i < 2; i++ {
+		anim.Image = append(anim.Image, image.NewPaletted(image.Rect(0, 0, 8, 8), palette))
+		anim.Delay = append(anim.Delay, 5)
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, gif.EncodeAll(buf, anim))
+
+	_, output := processImage(t, task, buf.Bytes())
+
+	out, err := gif.DecodeAll(bytes.NewReader(output))
+	require.NoError(t, err)
+	require.Len(t, out.Image, 2)
+	for _, frame := range out.Image {
+		require.Equal(t, uint8(1), frame.ColorIndexAt(1, 1))
+		require.Equal(t, uint8(0), frame.ColorIndexAt(6, 6))
+	}
+}
+
+func TestValidateRedactions(t *testing.T) {
+	require.Error(t, service.ValidateRedactions(nil))
+	require.Error(t, service.ValidateRedactions([]model.Redaction{{Width: 1, Height: 1, Method: "erase"}}))
+	require.Error(t, service.ValidateRedactions([]model.Redaction{{Width: 0, Height: 1, Method: service.RedactFill}}))
+	require.Error(t, service.ValidateRedactions([]model.Redaction{{Width: 1, Height: 1, Method: service.RedactFill, Color: "red"}}))
+	require.NoError(t, service.ValidateRedactions([]model.Redaction{{Width: 1, Height: 1, Method: service.RedactBlur, Radius: 4}}))
+}
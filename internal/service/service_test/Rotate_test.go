@@ -0,0 +1,180 @@
+package servicetest
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+	"ImageProcessor/internal/service"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	buf := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buf, img))
+	return buf.Bytes()
+}
+
+func processImage(t *testing.T, task model.ImageTask, input []byte) (model.ImageInRepo, []byte) {
+	store := mocks.NewMockImageStore(t)
+	store.On("Download", mock.Anything, task.UploadsPath).Return(io.NopCloser(bytes.NewReader(input)), nil).Once()
+
+	var output []byte
+	store.On("Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		data, err := io.ReadAll(args.Get(1).(io.Reader))
+		require.NoError(t, err)
+		output = data
+	}).Return(nil).Once()
+
+	res, err := service.ProcessImage(service.ImageService{
+		Ctx:          context.Background(),
+		ImageStorage: store,
+		Img:          task,
+	})
+	require.NoError(t, err)
+	return res, output
+}
+
+// marker returns a 3x2 image with a red pixel in the top-left corner.
+func marker() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			img.Set(x, y, color.RGBA{0, 0, 255, 255})
+		}
+	}
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	return img
+}
+
+func TestRotate(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	ptr := func(f float64) *float64 { return &f }
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name       string
+		params     model.ProcessingParams
+		expectedW  int
+		expectedH  int
+		expectedAt image.Point
+	}{
+		{
+			name:       "rotate 90",
+			params:     model.ProcessingParams{Angle: ptr(90)},
+			expectedW:  2,
+			expectedH:  3,
+			expectedAt: image.Pt(1, 0),
+		},
+		{
+			name:       "rotate 180",
+			params:     model.ProcessingParams{Angle: ptr(180)},
+			expectedW:  3,
+			expectedH:  2,
+			expectedAt: image.Pt(2, 1),
+		},
+		{
+			name:       "rotate -90",
+			params:     model.ProcessingParams{Angle: ptr(-90)},
+			expectedW:  2,
+			expectedH:  3,
+			expectedAt: image.Pt(0, 2),
+		},
+		{
+			name:       "flip horizontal",
+			params:     model.ProcessingParams{Flip: str(service.FlipHorizontal)},
+			expectedW:  3,
+			expectedH:  2,
+			expectedAt: image.Pt(2, 0),
+		},
+		{
+			name:       "flip vertical",
+			params:     model.ProcessingParams{Flip: str(service.FlipVertical)},
+			expectedW:  3,
+			expectedH:  2,
+			expectedAt: image.Pt(0, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := model.ImageTask{
+				ImageID:        1,
+				TypeProcessing: "rotate",
+				UploadsPath:    "uploads/test.png",
+				Parameters:     tt.params,
+			}
+
+			res, output := processImage(t, task, encodePNG(t, marker()))
+			require.Contains(t, res.ProcessedPath, "processed/rotated/")
+
+			img, err := png.Decode(bytes.NewReader(output))
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedW, img.Bounds().Dx())
+			require.Equal(t, tt.expectedH, img.Bounds().Dy())
+
+			r, g, b, a := img.At(tt.expectedAt.X, tt.expectedAt.Y).RGBA()
+			er, eg, eb, ea := red.RGBA()
+			require.Equal(t, []uint32{er, eg, eb, ea}, []uint32{r, g, b, a})
+		})
+	}
+}
+
+func TestRotateExpand(t *testing.T) {
+	angle := 45.0
+	expand := true
+	task := model.ImageTask{
+		TypeProcessing: "rotate",
+		UploadsPath:    "uploads/test.png",
+		Parameters:     model.ProcessingParams{Angle: &angle, Expand: &expand},
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	_, output := processImage(t, task, encodePNG(t, src))
+
+	img, err := png.Decode(bytes.NewReader(output))
+	require.NoError(t, err)
+	require.Equal(t, 142, img.Bounds().Dx())
+	require.Equal(t, 142, img.Bounds().Dy())
+}
+
+func TestRotateGIF(t *testing.T) {
+	angle := 90.0
+	task := model.ImageTask{
+		TypeProcessing: "rotate",
+		UploadsPath:    "uploads/test.gif",
+		Parameters:     model.ProcessingParams{Angle: &angle},
+	}
+
+	palette := color.Palette{color.RGBA{0, 0, 255, 255}, color.RGBA{255, 0, 0, 255}}
+	anim := &gif.GIF{}
+	for i := 0; i < 3; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 4, 2), palette)
+		frame.SetColorIndex(i, 0, 1)
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, gif.EncodeAll(buf, anim))
+
+	res, output := processImage(t, task, buf.Bytes())
+	require.Contains(t, res.ProcessedPath, ".gif")
+
+	out, err := gif.DecodeAll(bytes.NewReader(output))
+	require.NoError(t, err)
+	require.Len(t, out.Image, 3)
+	for i, frame := range out.Image {
+		require.Equal(t, 2, frame.Bounds().Dx())
+		require.Equal(t, 4, frame.Bounds().Dy())
+		require.Equal(t, uint8(1), frame.ColorIndexAt(1, i))
+	}
+}
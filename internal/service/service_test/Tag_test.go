@@ -0,0 +1,27 @@
+package servicetest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/service"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	for in, expected := range map[string]string{
+		"summer-2025": "summer-2025",
+		" Sale_EU ":   "sale_eu",
+		"Осень":       "осень",
+	} {
+		tag, err := service.NormalizeTag(in)
+		require.NoError(t, err)
+		require.Equal(t, expected, tag)
+	}
+
+	for _, in := range []string{"", "-sale", "two words", "a/b", strings.Repeat("a", 65)} {
+		_, err := service.NormalizeTag(in)
+		require.Error(t, err, in)
+	}
+}
@@ -0,0 +1,80 @@
+package servicetest
+
+import (
+	"context"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/service"
+)
+
+func TestNewTenants(t *testing.T) {
+	presets, err := service.NewPresets([]model.Preset{
+		{Name: "small", Width: 150, Height: 150, Mode: service.ResizeFill},
+		{Name: "large", Width: 800, Height: 600, Mode: service.ResizeFit},
+	}, "small")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		tenants   []model.TenantConfig
+		expectErr bool
+	}{
+		{name: "valid", tenants: []model.TenantConfig{{ID: "brand-a", Formats: []string{"jpeg"}, DefaultPreset: "large"}, {ID: "brand_b"}}},
+		{name: "none"},
+		{name: "invalid id", tenants: []model.TenantConfig{{ID: "Brand/A"}}, expectErr: true},
+		{name: "duplicate", tenants: []model.TenantConfig{{ID: "a"}, {ID: "a"}}, expectErr: true},
+		{name: "unknown format", tenants: []model.TenantConfig{{ID: "a", Formats: []string{"webp"}}}, expectErr: true},
+		{name: "unknown preset", tenants: []model.TenantConfig{{ID: "a", DefaultPreset: "huge"}}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.NewTenants(tt.tenants, presets)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	tenants, err := service.NewTenants([]model.TenantConfig{{ID: "brand", Formats: []string{"jpeg", "png"}, DefaultPreset: "large"}}, presets)
+	require.NoError(t, err)
+
+	require.True(t, tenants.AllowsFormat("brand", "png"))
+	require.False(t, tenants.AllowsFormat("brand", "gif"))
+	require.True(t, tenants.AllowsFormat("other", "gif"))
+	require.Equal(t, "large", tenants.Get("brand").DefaultPreset)
+	require.Equal(t, model.TenantConfig{ID: "other"}, tenants.Get("other"))
+}
+
+func TestTenantWatermarkRemoved(t *testing.T) {
+	watermark := "tenants/brand/watermarks/logo.png"
+	shared := "tenants/brand/transformations/logo.png"
+	objects := map[string][]byte{
+		"tenants/brand/uploads/test.png": encodePNG(t, filled(20, 20, color.NRGBA{0, 0, 255, 255})),
+		watermark:                        encodePNG(t, filled(5, 5, color.NRGBA{0, 0, 0, 0})),
+		shared:                           encodePNG(t, filled(5, 5, color.NRGBA{0, 0, 0, 0})),
+	}
+
+	for _, path := range []string{watermark, shared} {
+		_, err := service.ProcessImage(service.ImageService{
+			Ctx:          context.Background(),
+			ImageStorage: memoryStore(t, objects),
+			Img: model.ImageTask{
+				Tenant:         "brand",
+				TypeProcessing: "watermark",
+				UploadsPath:    "tenants/brand/uploads/test.png",
+				Parameters:     model.ProcessingParams{WatermarkPath: &path},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	require.NotContains(t, objects, watermark)
+	require.Contains(t, objects, shared)
+}
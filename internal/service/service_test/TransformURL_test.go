@@ -0,0 +1,85 @@
+package servicetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/service"
+)
+
+func TestParseURLOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   string
+		canonical string
+		expectErr bool
+	}{
+		{name: "width only", options: "w_200", canonical: "w_200,m_fit"},
+		{name: "reordered", options: "q_80,f_jpeg,m_fill,h_300,w_400,g_smart", canonical: "w_400,h_300,m_fill,g_smart,f_jpeg,q_80"},
+		{name: "crop", options: "w_10,h_10,m_crop,g_north", canonical: "w_10,h_10,m_crop,g_north"},
+		{name: "no size", options: "f_png", expectErr: true},
+		{name: "fill without height", options: "w_10,m_fill", expectErr: true},
+		{name: "too large", options: "w_100000", expectErr: true},
+		{name: "unknown key", options: "w_10,x_1", expectErr: true},
+		{name: "bad format", options: "w_10,f_webp", expectErr: true},
+		{name: "missing value", options: "w_", expectErr: true},
+		{name: "zero quality", options: "w_10,q_0", expectErr: true},
+		{name: "quality above 100", options: "w_10,q_101", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := service.ParseURLOptions(tt.options)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.canonical, opts.String())
+			require.Equal(t, "processed/t/3/"+tt.canonical, opts.VariantName(3))
+		})
+	}
+}
+
+func TestURLOptionsTask(t *testing.T) {
+	opts, err := service.ParseURLOptions("w_10,h_20,m_crop,g_east,f_jpeg")
+	require.NoError(t, err)
+
+	task, err := opts.Task(model.ImageInRepo{ID: 3, UploadsPath: "uploads/3.png", Processed: true})
+	require.NoError(t, err)
+	require.Equal(t, "crop", task.TypeProcessing)
+	require.Equal(t, "uploads/3.png", task.UploadsPath)
+	require.Equal(t, 10, *task.Parameters.Width)
+	require.Equal(t, 20, *task.Parameters.Height)
+	require.Equal(t, service.GravityEast, *task.Parameters.Gravity)
+	require.Equal(t, service.FormatJPEG, *task.Parameters.Format)
+
+	task, err = opts.Task(model.ImageInRepo{
+		ID:            3,
+		UploadsPath:   "uploads/3.png",
+		ProcessedPath: "processed/3.png",
+		Processed:     true,
+		Result:        &model.ProcessingResult{Redactions: []model.Redaction{{Width: 1, Height: 1}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "processed/3.png", task.UploadsPath)
+
+	_, err = opts.Task(model.ImageInRepo{ID: 3, UploadsPath: "uploads/3.png"})
+	require.ErrorIs(t, err, service.ErrNotProcessed)
+}
+
+func TestVerifyURL(t *testing.T) {
+	signature := service.SignURL("secret", "", "w_10", 3)
+
+	require.NoError(t, service.VerifyURL("secret", signature, "", "w_10", 3))
+	require.ErrorIs(t, service.VerifyURL("secret", signature, "", "w_11", 3), service.ErrBadSignature)
+	require.ErrorIs(t, service.VerifyURL("secret", signature, "", "w_10", 4), service.ErrBadSignature)
+	require.ErrorIs(t, service.VerifyURL("other", signature, "", "w_10", 3), service.ErrBadSignature)
+	require.ErrorIs(t, service.VerifyURL("", service.SignURL("", "", "w_10", 3), "", "w_10", 3), service.ErrBadSignature)
+
+	require.NoError(t, service.VerifyURL("secret", signature, "default", "w_10", 3))
+	require.ErrorIs(t, service.VerifyURL("secret", signature, "brand", "w_10", 3), service.ErrBadSignature)
+	require.NoError(t, service.VerifyURL("secret", service.SignURL("secret", "brand", "w_10", 3), "brand", "w_10", 3))
+}
@@ -0,0 +1,123 @@
+package servicetest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+)
+
+func framed(w, h int, content image.Rectangle, border, fill color.NRGBA) *image.NRGBA {
+	img := filled(w, h, border)
+	for y := content.Min.Y; y < content.Max.Y; y++ {
+		for x := content.Min.X; x < content.Max.X; x++ {
+			img.SetNRGBA(x, y, fill)
+		}
+	}
+	return img
+}
+
+func TestTrim(t *testing.T) {
+	white := color.NRGBA{255, 255, 255, 255}
+	offWhite := color.NRGBA{250, 250, 250, 255}
+	black := color.NRGBA{0, 0, 0, 255}
+	intPtr := func(v int) *int { return &v }
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		params   model.ProcessingParams
+		input    image.Image
+		expected model.Rect
+	}{
+		{
+			name:     "top-left border color",
+			input:    framed(20, 10, image.Rect(5, 2, 12, 8), white, black),
+			expected: model.Rect{X: 5, Y: 2, Width: 7, Height: 6},
+		},
+		{
+			name:     "padding is clamped to bounds",
+			params:   model.ProcessingParams{Padding: intPtr(3)},
+			input:    framed(20, 10, image.Rect(5, 2, 12, 8), white, black),
+			expected: model.Rect{X: 2, Y: 0, Width: 13, Height: 10},
+		},
+		{
+			name:     "tolerance ignores noise",
+			params:   model.ProcessingParams{Tolerance: intPtr(10)},
+			input:    noisy(framed(20, 10, image.Rect(5, 2, 12, 8), white, black), offWhite),
+			expected: model.Rect{X: 5, Y: 2, Width: 7, Height: 6},
+		},
+		{
+			name:     "explicit color",
+			params:   model.ProcessingParams{TrimColor: str("#000")},
+			input:    framed(10, 10, image.Rect(0, 0, 10, 4), black, white),
+			expected: model.Rect{X: 0, Y: 0, Width: 10, Height: 4},
+		},
+		{
+			name:     "uniform image is kept",
+			input:    filled(6, 4, white),
+			expected: model.Rect{X: 0, Y: 0, Width: 6, Height: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := model.ImageTask{
+				TypeProcessing: "trim",
+				UploadsPath:    "uploads/test.png",
+				Parameters:     tt.params,
+			}
+
+			res, output := processImage(t, task, encodePNG(t, tt.input))
+			require.Contains(t, res.ProcessedPath, "processed/trimmed/")
+			require.NotNil(t, res.Result)
+			require.Equal(t, tt.expected, *res.Result.TrimBox)
+
+			img, err := png.Decode(bytes.NewReader(output))
+			require.NoError(t, err)
+			require.Equal(t, image.Pt(tt.expected.Width, tt.expected.Height), img.Bounds().Size())
+		})
+	}
+}
+
+func noisy(img *image.NRGBA, noise color.NRGBA) *image.NRGBA {
+	img.SetNRGBA(1, 1, noise)
+	img.SetNRGBA(18, 9, noise)
+	return img
+}
+
+func TestTrimGIF(t *testing.T) {
+	task := model.ImageTask{
+		TypeProcessing: "trim",
+		UploadsPath:    "uploads/test.gif",
+	}
+
+	palette := color.Palette{color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 255}}
+	anim := &gif.GIF{}
+	for i, p := range []image.Point{{2, 2}, {6, 5}} {
+		frame := image.NewPaletted(image.Rect(0, 0, 10, 10), palette)
+		frame.SetColorIndex(p.X, p.Y, 1)
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, i)
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, gif.EncodeAll(buf, anim))
+
+	res, output := processImage(t, task, buf.Bytes())
+	require.Equal(t, model.Rect{X: 2, Y: 2, Width: 5, Height: 4}, *res.Result.TrimBox)
+
+	out, err := gif.DecodeAll(bytes.NewReader(output))
+	require.NoError(t, err)
+	require.Len(t, out.Image, 2)
+	for _, frame := range out.Image {
+		require.Equal(t, image.Pt(5, 4), frame.Bounds().Size())
+	}
+	require.Equal(t, uint8(1), out.Image[0].ColorIndexAt(0, 0))
+	require.Equal(t, uint8(1), out.Image[1].ColorIndexAt(4, 3))
+}
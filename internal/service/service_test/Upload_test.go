@@ -0,0 +1,66 @@
+package servicetest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/color"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ImageProcessor/internal/model"
+	"ImageProcessor/internal/repository/mocks"
+	"ImageProcessor/internal/service"
+)
+
+func TestSaveStreamsIntoStore(t *testing.T) {
+	input := encodePNG(t, filled(600, 400, color.NRGBA{0, 128, 255, 255}))
+	width, height := 500, 300
+	task := model.ImageTask{
+		TypeProcessing: "resize",
+		UploadsPath:    "uploads/test.png",
+		Parameters:     model.ProcessingParams{Width: &width, Height: &height},
+	}
+
+	t.Run("unknown size", func(t *testing.T) {
+		store := mocks.NewMockImageStore(t)
+		store.EXPECT().Download(mock.Anything, "uploads/test.png").Return(io.NopCloser(bytes.NewReader(input)), nil).Once()
+
+		var uploaded []byte
+		store.EXPECT().Upload(mock.Anything, mock.Anything, mock.Anything, int64(-1)).RunAndReturn(func(_ context.Context, r io.Reader, _ string, _ int64) error {
+			var err error
+			uploaded, err = io.ReadAll(r)
+			return err
+		}).Once()
+
+		_, err := service.ProcessImage(service.ImageService{Ctx: context.Background(), ImageStorage: store, Img: task})
+		require.NoError(t, err)
+		require.NotEmpty(t, uploaded)
+	})
+
+	t.Run("store fails before reading", func(t *testing.T) {
+		store := mocks.NewMockImageStore(t)
+		store.EXPECT().Download(mock.Anything, "uploads/test.png").Return(io.NopCloser(bytes.NewReader(input)), nil).Once()
+
+		storeErr := fmt.Errorf("bucket unavailable")
+		store.EXPECT().Upload(mock.Anything, mock.Anything, mock.Anything, int64(-1)).Return(storeErr).Once()
+
+		_, err := service.ProcessImage(service.ImageService{Ctx: context.Background(), ImageStorage: store, Img: task})
+		require.ErrorIs(t, err, storeErr)
+	})
+
+	t.Run("store stops reading early", func(t *testing.T) {
+		store := mocks.NewMockImageStore(t)
+		store.EXPECT().Download(mock.Anything, "uploads/test.png").Return(io.NopCloser(bytes.NewReader(input)), nil).Once()
+		store.EXPECT().Upload(mock.Anything, mock.Anything, mock.Anything, int64(-1)).RunAndReturn(func(_ context.Context, r io.Reader, _ string, _ int64) error {
+			_, err := r.Read(make([]byte, 16))
+			return err
+		}).Once()
+
+		_, err := service.ProcessImage(service.ImageService{Ctx: context.Background(), ImageStorage: store, Img: task})
+		require.ErrorIs(t, err, io.ErrClosedPipe)
+	})
+}
@@ -0,0 +1 @@
+ALTER TABLE image_path DROP COLUMN IF EXISTS result;
\ No newline at end of file
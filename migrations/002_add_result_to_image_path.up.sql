@@ -0,0 +1 @@
+ALTER TABLE image_path ADD COLUMN IF NOT EXISTS result JSONB;
\ No newline at end of file
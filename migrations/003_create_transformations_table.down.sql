@@ -0,0 +1 @@
+DROP TABLE transformations;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS transformations (
+    name VARCHAR(64) PRIMARY KEY,
+    steps JSONB NOT NULL,
+    format VARCHAR(10) NOT NULL DEFAULT '',
+    quality INTEGER NOT NULL DEFAULT 0,
+    watermark_path VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
\ No newline at end of file
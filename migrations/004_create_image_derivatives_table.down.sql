@@ -0,0 +1 @@
+DROP TABLE image_derivatives;
\ No newline at end of file
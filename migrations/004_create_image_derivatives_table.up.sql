@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS image_derivatives (
+    id SERIAL PRIMARY KEY,
+    image_id INTEGER NOT NULL REFERENCES image_path (id) ON DELETE CASCADE,
+    type_processing VARCHAR(32) NOT NULL,
+    processed_path VARCHAR(255) NOT NULL,
+    processed BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    result JSONB
+);
+
+CREATE INDEX IF NOT EXISTS image_derivatives_image_id_idx ON image_derivatives (image_id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS image_path_owner_idx;
+
+ALTER TABLE image_path
+    DROP COLUMN IF EXISTS visibility,
+    DROP COLUMN IF EXISTS owner;
\ No newline at end of file
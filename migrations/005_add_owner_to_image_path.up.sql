@@ -0,0 +1,5 @@
+ALTER TABLE image_path
+    ADD COLUMN IF NOT EXISTS owner VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS visibility VARCHAR(16) NOT NULL DEFAULT 'public';
+
+CREATE INDEX IF NOT EXISTS image_path_owner_idx ON image_path (owner);
\ No newline at end of file
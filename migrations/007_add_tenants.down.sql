@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS api_keys_tenant_user_id_idx;
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id);
+
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS tenant;
+
+ALTER TABLE transformations DROP CONSTRAINT IF EXISTS transformations_pkey;
+DELETE FROM transformations WHERE tenant <> 'default';
+ALTER TABLE transformations ADD PRIMARY KEY (name);
+
+ALTER TABLE transformations
+    DROP COLUMN IF EXISTS tenant;
+
+DROP INDEX IF EXISTS image_path_tenant_created_at_idx;
+
+ALTER TABLE image_path
+    DROP COLUMN IF EXISTS tenant;
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE image_path
+    ADD COLUMN IF NOT EXISTS tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS image_path_tenant_created_at_idx ON image_path (tenant, created_at, id);
+
+ALTER TABLE transformations
+    ADD COLUMN IF NOT EXISTS tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+ALTER TABLE transformations DROP CONSTRAINT IF EXISTS transformations_pkey;
+ALTER TABLE transformations ADD PRIMARY KEY (tenant, name);
+
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX IF EXISTS api_keys_user_id_idx;
+CREATE INDEX IF NOT EXISTS api_keys_tenant_user_id_idx ON api_keys (tenant, user_id);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS storage_usage;
+
+ALTER TABLE image_path
+    DROP COLUMN IF EXISTS size;
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE image_path
+    ADD COLUMN IF NOT EXISTS size BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS storage_usage (
+    tenant VARCHAR(64) NOT NULL,
+    owner VARCHAR(255) NOT NULL,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    images INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant, owner)
+);
+
+INSERT INTO storage_usage (tenant, owner, bytes, images)
+SELECT tenant, COALESCE(owner, ''), 0, COUNT(*)
+FROM image_path
+GROUP BY tenant, COALESCE(owner, '')
+ON CONFLICT (tenant, owner) DO NOTHING;
\ No newline at end of file
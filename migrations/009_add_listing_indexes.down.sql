@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS image_path_tenant_owner_created_at_idx;
+DROP INDEX IF EXISTS image_path_tenant_size_idx;
\ No newline at end of file
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS image_path_tenant_size_idx ON image_path (tenant, size, id);
+CREATE INDEX IF NOT EXISTS image_path_tenant_owner_created_at_idx ON image_path (tenant, owner, created_at, id);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS album_images;
+DROP TABLE IF EXISTS albums;
+DROP TABLE IF EXISTS image_tags;
+DROP TABLE IF EXISTS tags;
\ No newline at end of file
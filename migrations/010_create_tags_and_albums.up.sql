@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    tenant VARCHAR(64) NOT NULL DEFAULT 'default',
+    name VARCHAR(64) NOT NULL,
+    UNIQUE (tenant, name)
+);
+
+CREATE TABLE IF NOT EXISTS image_tags (
+    image_id INTEGER NOT NULL REFERENCES image_path (id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (image_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS image_tags_tag_id_idx ON image_tags (tag_id, image_id);
+
+CREATE TABLE IF NOT EXISTS albums (
+    id SERIAL PRIMARY KEY,
+    tenant VARCHAR(64) NOT NULL DEFAULT 'default',
+    owner VARCHAR(255),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS albums_tenant_owner_idx ON albums (tenant, owner);
+
+CREATE TABLE IF NOT EXISTS album_images (
+    album_id INTEGER NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    image_id INTEGER NOT NULL REFERENCES image_path (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (album_id, image_id)
+);
+
+CREATE INDEX IF NOT EXISTS album_images_image_id_idx ON album_images (image_id);
\ No newline at end of file
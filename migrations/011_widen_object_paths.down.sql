@@ -0,0 +1,9 @@
+ALTER TABLE transformations
+    ALTER COLUMN watermark_path TYPE VARCHAR(100);
+
+ALTER TABLE image_derivatives
+    ALTER COLUMN processed_path TYPE VARCHAR(255);
+
+ALTER TABLE image_path
+    ALTER COLUMN processed_path TYPE VARCHAR(100),
+    ALTER COLUMN uploads_path TYPE VARCHAR(100);
\ No newline at end of file
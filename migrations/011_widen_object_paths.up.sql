@@ -0,0 +1,9 @@
+ALTER TABLE image_path
+    ALTER COLUMN uploads_path TYPE TEXT,
+    ALTER COLUMN processed_path TYPE TEXT;
+
+ALTER TABLE image_derivatives
+    ALTER COLUMN processed_path TYPE TEXT;
+
+ALTER TABLE transformations
+    ALTER COLUMN watermark_path TYPE TEXT;
\ No newline at end of file